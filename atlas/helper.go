@@ -7,15 +7,7 @@ import (
 	"sync"
 
 	"github.com/DNS-OARC/ripeatlas/measurement"
-	"github.com/czerwonk/atlas_exporter/config"
-	"github.com/czerwonk/atlas_exporter/dns"
-	"github.com/czerwonk/atlas_exporter/exporter"
-	"github.com/czerwonk/atlas_exporter/http"
-	"github.com/czerwonk/atlas_exporter/ntp"
-	"github.com/czerwonk/atlas_exporter/ping"
 	"github.com/czerwonk/atlas_exporter/probe"
-	"github.com/czerwonk/atlas_exporter/sslcert"
-	"github.com/czerwonk/atlas_exporter/traceroute"
 )
 
 func probesForResults(res []*measurement.Result, workers uint) (map[int]*probe.Probe, error) {
@@ -100,22 +92,3 @@ func probeForID(id int) (*probe.Probe, error) {
 	cache.Add(id, p)
 	return p, nil
 }
-
-func measurementForType(t, id, ipVersion string, cfg *config.Config) (*exporter.Measurement, error) {
-	switch t {
-	case "ping":
-		return ping.NewMeasurement(id, ipVersion, cfg), nil
-	case "traceroute":
-		return traceroute.NewMeasurement(id, ipVersion, cfg), nil
-	case "ntp":
-		return ntp.NewMeasurement(id, cfg), nil
-	case "dns":
-		return dns.NewMeasurement(id, ipVersion, cfg), nil
-	case "http":
-		return http.NewMeasurement(id, ipVersion, cfg), nil
-	case "sslcert":
-		return sslcert.NewMeasurement(id, cfg), nil
-	}
-
-	return nil, fmt.Errorf("type %s is not supported yet", t)
-}
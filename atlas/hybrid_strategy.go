@@ -0,0 +1,257 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package atlas
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/DNS-OARC/ripeatlas"
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/czerwonk/atlas_exporter/config"
+	"github.com/czerwonk/atlas_exporter/exporter"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	modeStreaming = "streaming"
+	modePolling   = "polling"
+)
+
+var modes = []string{modeStreaming, modePolling}
+
+// streamer subscribes to the results of a measurement and returns the number of results received until disconnected
+type streamer interface {
+	stream(ctx context.Context) int
+}
+
+// poller retrieves the results of a measurement since the last poll
+type poller interface {
+	poll() error
+}
+
+type pollerFunc func() error
+
+func (f pollerFunc) poll() error {
+	return f()
+}
+
+type hybridMeasurement struct {
+	*polledMeasurement
+	cfg     config.Measurement
+	polling atomic.Bool
+	// fallback is signaled when falling back to polling, so the first poll does not wait for the interval
+	fallback chan struct{}
+}
+
+func newHybridMeasurement(m config.Measurement) *hybridMeasurement {
+	return &hybridMeasurement{
+		polledMeasurement: newPolledMeasurement(m.ID),
+		cfg:               m,
+		fallback:          make(chan struct{}, 1),
+	}
+}
+
+func (m *hybridMeasurement) mode() string {
+	if m.polling.Load() {
+		return modePolling
+	}
+
+	return modeStreaming
+}
+
+type hybridStrategy struct {
+	atlasser        ripeatlas.Atlaser
+	cfg             *config.Config
+	workers         uint
+	defaultTimeout  time.Duration
+	fallbackAfter   uint
+	pollingInterval time.Duration
+	retryInterval   time.Duration
+	measurements    map[string]*hybridMeasurement
+	modeDesc        *prometheus.Desc
+}
+
+// NewHybridStrategy returns a strategy using the RIPE Atlas Streaming API. When no results could be received
+// for a measurement on `fallbackAfter` consecutive connections, its results are polled from Atlas API until
+// streaming recovers (streaming is retried while polling). The strategy exports the current mode of each
+// measurement as metric.
+func NewHybridStrategy(ctx context.Context, cfg *config.Config, bufferSize, workers uint, defaultTimeout time.Duration, fallbackAfter uint, pollingInterval time.Duration) Strategy {
+	s := newHybridStrategy(cfg, ripeatlas.Atlaser(ripeatlas.NewHttp()), workers, defaultTimeout, fallbackAfter, pollingInterval)
+
+	resultCh := make(chan *measurement.Result, int(bufferSize))
+	for _, m := range s.measurements {
+		w := &streamStrategyWorker{
+			atlasser:    ripeatlas.NewStream(),
+			resultCh:    resultCh,
+			measurement: m.cfg,
+			timeout:     s.timeoutForMeasurement(m.cfg),
+		}
+		go s.stream(ctx, m, w)
+
+		p := pollerFunc(func() error {
+			return m.update(s.atlasser, s.cfg, s.workers)
+		})
+		go s.poll(ctx, m, p)
+	}
+
+	go s.processMeasurementResults(ctx, resultCh)
+
+	return s
+}
+
+func newHybridStrategy(cfg *config.Config, atlasser ripeatlas.Atlaser, workers uint, defaultTimeout time.Duration, fallbackAfter uint, pollingInterval time.Duration) *hybridStrategy {
+	s := &hybridStrategy{
+		atlasser:        atlasser,
+		cfg:             cfg,
+		workers:         workers,
+		defaultTimeout:  defaultTimeout,
+		fallbackAfter:   fallbackAfter,
+		pollingInterval: pollingInterval,
+		retryInterval:   connectionRetryInterval,
+		measurements:    make(map[string]*hybridMeasurement),
+		modeDesc:        prometheus.NewDesc("atlas_retrieval_mode", "Mode results of the measurement are currently retrieved by (streaming, polling)", []string{"measurement", "mode"}, nil),
+	}
+
+	for _, m := range cfg.Measurements {
+		s.measurements[m.ID] = newHybridMeasurement(m)
+	}
+
+	return s
+}
+
+// stream connects to the Streaming API until the context is cancelled and falls back to polling after
+// `fallbackAfter` consecutive connections without results
+func (s *hybridStrategy) stream(ctx context.Context, m *hybridMeasurement, st streamer) {
+	var failures uint
+	for {
+		if st.stream(ctx) > 0 {
+			failures = 0
+		} else {
+			failures++
+		}
+
+		if failures >= s.fallbackAfter && !m.polling.Swap(true) {
+			log.Warnf("No results received for measurement #%s on %d connections. Falling back to polling.", m.id, failures)
+
+			select {
+			case m.fallback <- struct{}{}:
+			default:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.retryInterval):
+			continue
+		}
+	}
+}
+
+// poll polls the results of the measurement in the polling interval as long as the fallback is active
+func (s *hybridStrategy) poll(ctx context.Context, m *hybridMeasurement, p poller) {
+	ticker := time.NewTicker(s.pollingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.fallback:
+			ticker.Reset(s.pollingInterval)
+		case <-ticker.C:
+		}
+
+		if !m.polling.Load() {
+			continue
+		}
+
+		err := p.poll()
+		if err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+func (s *hybridStrategy) timeoutForMeasurement(m config.Measurement) time.Duration {
+	if m.Timeout == 0 {
+		return s.defaultTimeout
+	}
+
+	return m.Timeout
+}
+
+func (s *hybridStrategy) processMeasurementResults(ctx context.Context, resultCh <-chan *measurement.Result) {
+	for {
+		select {
+		case r := <-resultCh:
+			s.processMeasurementResult(r)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *hybridStrategy) processMeasurementResult(r *measurement.Result) {
+	log.Infof("Got result for %d from probe %d", r.MsmId(), r.PrbId())
+
+	m, found := s.measurements[strconv.Itoa(r.MsmId())]
+	if !found {
+		return
+	}
+
+	if m.polling.Swap(false) {
+		log.Infof("Streaming recovered for measurement #%s. Stopped polling.", m.id)
+	}
+
+	probe, err := probeForID(r.PrbId())
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	err = m.add(r, probe, s.cfg)
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+func (s *hybridStrategy) MeasurementResults(ctx context.Context, ids []string) ([]*exporter.Measurement, error) {
+	result := make([]*exporter.Measurement, 0)
+	for _, id := range ids {
+		m, found := s.measurements[id]
+		if !found {
+			continue
+		}
+
+		if mes := m.current(); mes != nil {
+			result = append(result, mes)
+		}
+	}
+
+	return result, nil
+}
+
+// Describe describes the metrics of the strategy
+func (s *hybridStrategy) Describe(ch chan<- *prometheus.Desc) {
+	ch <- s.modeDesc
+}
+
+// Collect collects the metrics of the strategy
+func (s *hybridStrategy) Collect(ch chan<- prometheus.Metric) {
+	for id, m := range s.measurements {
+		current := m.mode()
+		for _, mode := range modes {
+			v := 0.0
+			if mode == current {
+				v = 1
+			}
+
+			ch <- prometheus.MustNewConstMetric(s.modeDesc, prometheus.GaugeValue, v, id, mode)
+		}
+	}
+}
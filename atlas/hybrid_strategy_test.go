@@ -0,0 +1,291 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package atlas
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DNS-OARC/ripeatlas"
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/czerwonk/atlas_exporter/config"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeStreamer returns the scripted number of results for each connection (0 if the script is exhausted).
+// When blocking is set, connections without scripted results last until the context is cancelled.
+type fakeStreamer struct {
+	received []int
+	blocking bool
+	calls    int
+	mu       sync.Mutex
+}
+
+func (f *fakeStreamer) stream(ctx context.Context) int {
+	f.mu.Lock()
+	f.calls++
+	if len(f.received) > 0 {
+		n := f.received[0]
+		f.received = f.received[1:]
+		f.mu.Unlock()
+		return n
+	}
+	f.mu.Unlock()
+
+	if f.blocking {
+		<-ctx.Done()
+	}
+
+	return 0
+}
+
+func (f *fakeStreamer) connections() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.calls
+}
+
+// fakePoller counts the polls
+type fakePoller struct {
+	polls int
+	mu    sync.Mutex
+}
+
+func (f *fakePoller) poll() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.polls++
+	return nil
+}
+
+func (f *fakePoller) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.polls
+}
+
+// fakeStream sends the scripted results on each subscription and closes the channel afterwards
+type fakeStream struct {
+	fakeAtlaser
+	results []*measurement.Result
+	subs    int
+}
+
+func (f *fakeStream) MeasurementResults(p ripeatlas.Params) (<-chan *measurement.Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.subs++
+
+	ch := make(chan *measurement.Result, len(f.results))
+	for _, r := range f.results {
+		ch <- r
+	}
+	close(ch)
+
+	return ch, nil
+}
+
+func (f *fakeStream) subscriptions() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.subs
+}
+
+func newTestHybridStrategy(fallbackAfter uint, pollingInterval time.Duration, ids ...string) *hybridStrategy {
+	cfg := &config.Config{}
+	for _, id := range ids {
+		cfg.Measurements = append(cfg.Measurements, config.Measurement{ID: id})
+	}
+
+	s := newHybridStrategy(cfg, &fakeAtlaser{}, 1, time.Minute, fallbackAfter, pollingInterval)
+	s.retryInterval = time.Millisecond
+
+	return s
+}
+
+func TestHybridStrategyFallback(t *testing.T) {
+	tests := []struct {
+		name            string
+		received        []int
+		connections     int
+		expectedPolling bool
+	}{
+		{
+			name:            "fallback after empty connections",
+			received:        []int{0, 0, 0},
+			connections:     3,
+			expectedPolling: true,
+		},
+		{
+			name:        "not enough empty connections",
+			received:    []int{0, 0},
+			connections: 2,
+		},
+		{
+			name:        "results reset empty connections",
+			received:    []int{0, 0, 5, 0, 0, 1},
+			connections: 6,
+		},
+		{
+			name:            "fallback after empty connections following results",
+			received:        []int{0, 0, 5, 0, 0, 0},
+			connections:     6,
+			expectedPolling: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(te *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			s := newTestHybridStrategy(3, time.Hour, "1")
+			m := s.measurements["1"]
+
+			// blocks after the script, so the state is checked after the scripted connections
+			st := &fakeStreamer{received: test.received, blocking: true}
+			go s.stream(ctx, m, st)
+
+			assert.Eventually(te, func() bool {
+				return st.connections() > test.connections
+			}, time.Second, time.Millisecond)
+			assert.Equal(te, test.expectedPolling, m.polling.Load())
+		})
+	}
+}
+
+func TestHybridStrategyPollsIndependentOfConnections(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := newTestHybridStrategy(1, 5*time.Millisecond, "1")
+	m := s.measurements["1"]
+
+	st := &fakeStreamer{received: []int{0}, blocking: true}
+	p := &fakePoller{}
+	go s.stream(ctx, m, st)
+	go s.poll(ctx, m, p)
+
+	assert.Eventually(t, func() bool {
+		return p.count() >= 3
+	}, time.Second, time.Millisecond, "polls while a connection is open")
+	assert.Equal(t, 2, st.connections())
+}
+
+func TestHybridStrategyPollsOnFallback(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := newTestHybridStrategy(2, time.Hour, "1")
+	m := s.measurements["1"]
+
+	st := &fakeStreamer{}
+	p := &fakePoller{}
+	go s.poll(ctx, m, p)
+
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, 0, p.count(), "no polls while streaming")
+
+	go s.stream(ctx, m, st)
+
+	assert.Eventually(t, func() bool {
+		return p.count() == 1
+	}, time.Second, time.Millisecond, "first poll right after falling back")
+
+	assert.Eventually(t, func() bool {
+		return st.connections() > 10
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, 1, p.count(), "reconnects do not trigger polls")
+}
+
+func TestHybridStrategyRecovers(t *testing.T) {
+	initTestCache(1)
+	s := newTestHybridStrategy(1, time.Hour, "1", "2")
+	m := s.measurements["1"]
+	m.polling.Store(true)
+	s.measurements["2"].polling.Store(true)
+
+	s.processMeasurementResult(pingResult(t, 1, 1000))
+
+	assert.False(t, m.polling.Load())
+	assert.True(t, s.measurements["2"].polling.Load())
+	assert.Equal(t, uint64(1), rttCount(t, m.current()))
+}
+
+func TestHybridStrategyRetrievalMode(t *testing.T) {
+	s := newTestHybridStrategy(1, time.Hour, "1", "2")
+	s.measurements["2"].polling.Store(true)
+
+	expected := `
+# HELP atlas_retrieval_mode Mode results of the measurement are currently retrieved by (streaming, polling)
+# TYPE atlas_retrieval_mode gauge
+atlas_retrieval_mode{measurement="1",mode="polling"} 0
+atlas_retrieval_mode{measurement="1",mode="streaming"} 1
+atlas_retrieval_mode{measurement="2",mode="polling"} 1
+atlas_retrieval_mode{measurement="2",mode="streaming"} 0
+`
+	assert.NoError(t, testutil.CollectAndCompare(s, strings.NewReader(expected)))
+
+	s.measurements["2"].polling.Store(false)
+	expected = strings.ReplaceAll(expected, `measurement="2",mode="polling"} 1`, `measurement="2",mode="polling"} 0`)
+	expected = strings.ReplaceAll(expected, `measurement="2",mode="streaming"} 0`, `measurement="2",mode="streaming"} 1`)
+	assert.NoError(t, testutil.CollectAndCompare(s, strings.NewReader(expected)))
+}
+
+func TestHybridStrategyFallbackOnClosedStream(t *testing.T) {
+	tests := []struct {
+		name            string
+		results         []*measurement.Result
+		expectedPolling bool
+	}{
+		{
+			name:            "closed without results",
+			expectedPolling: true,
+		},
+		{
+			name:            "closed after results failing to parse",
+			results:         []*measurement.Result{{ParseError: errors.New("invalid result")}},
+			expectedPolling: true,
+		},
+		{
+			name:    "closed after results",
+			results: []*measurement.Result{pingResult(t, 1, 1000)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(te *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			s := newTestHybridStrategy(2, time.Hour, "1")
+			m := s.measurements["1"]
+
+			resultCh := make(chan *measurement.Result, 100)
+			st := &fakeStream{results: test.results}
+			w := &streamStrategyWorker{
+				atlasser:    st,
+				resultCh:    resultCh,
+				measurement: m.cfg,
+				timeout:     time.Hour,
+			}
+			go s.stream(ctx, m, w)
+
+			assert.Eventually(te, func() bool {
+				return st.subscriptions() > 3
+			}, time.Second, time.Millisecond, "reconnects when the stream is closed")
+			assert.Equal(te, test.expectedPolling, m.polling.Load())
+			assert.Equal(te, !test.expectedPolling, len(resultCh) > 0, "only parsed results are forwarded")
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package atlas
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/DNS-OARC/ripeatlas"
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/czerwonk/atlas_exporter/config"
+	"github.com/czerwonk/atlas_exporter/exporter"
+	"github.com/czerwonk/atlas_exporter/probe"
+)
+
+// lateResultWindow is how far results are requested again before the latest result seen. Results can be reported
+// late by probes, so results of a probe within this window not seen before are added as well.
+const lateResultWindow = 10 * time.Minute
+
+// polledMeasurement is a measurement retrieved by requests to the Atlas API. The measurement is kept between
+// updates and only results newer than the latest result seen for the probe are added, so histograms get each
+// result once.
+type polledMeasurement struct {
+	id              string
+	measurement     *exporter.Measurement
+	lastTimestamp   int
+	probeTimestamps map[int]int
+	mu              sync.Mutex
+}
+
+func newPolledMeasurement(id string) *polledMeasurement {
+	return &polledMeasurement{
+		id:              id,
+		probeTimestamps: make(map[int]int),
+	}
+}
+
+// update retrieves the results since the last update (latest results on first update) and adds them.
+// The results and their probes are retrieved without holding the lock, so results can be added concurrently.
+func (m *polledMeasurement) update(atlasser ripeatlas.Atlaser, cfg *config.Config, workers uint) error {
+	res, err := m.newResults(atlasser)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	res = m.unseen(res)
+	m.mu.Unlock()
+
+	if len(res) == 0 {
+		return nil
+	}
+
+	probes, err := probesForResults(res, workers)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	err = m.init(res[0], cfg)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range m.unseen(res) {
+		m.measurement.Add(r, probes[r.PrbId()])
+		m.seen(r)
+	}
+
+	return nil
+}
+
+// add adds a result retrieved by other means (e.g. Streaming API) unless it was already seen
+func (m *polledMeasurement) add(r *measurement.Result, p *probe.Probe, cfg *config.Config) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r.Timestamp() <= m.probeTimestamps[r.PrbId()] {
+		return nil
+	}
+
+	err := m.init(r, cfg)
+	if err != nil {
+		return err
+	}
+
+	m.measurement.Add(r, p)
+	m.seen(r)
+
+	return nil
+}
+
+// unseen returns the results (sorted by timestamp) newer than the latest result seen for their probe
+func (m *polledMeasurement) unseen(res []*measurement.Result) []*measurement.Result {
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Timestamp() < res[j].Timestamp()
+	})
+
+	latest := make(map[int]int)
+	unseen := make([]*measurement.Result, 0, len(res))
+	for _, r := range res {
+		ts, found := latest[r.PrbId()]
+		if !found {
+			ts = m.probeTimestamps[r.PrbId()]
+		}
+
+		if r.Timestamp() > ts {
+			unseen = append(unseen, r)
+			latest[r.PrbId()] = r.Timestamp()
+		}
+	}
+
+	return unseen
+}
+
+// seen advances the latest result seen for the probe of the result and the whole measurement
+func (m *polledMeasurement) seen(r *measurement.Result) {
+	if r.Timestamp() > m.probeTimestamps[r.PrbId()] {
+		m.probeTimestamps[r.PrbId()] = r.Timestamp()
+	}
+
+	if r.Timestamp() > m.lastTimestamp {
+		m.lastTimestamp = r.Timestamp()
+	}
+}
+
+func (m *polledMeasurement) init(first *measurement.Result, cfg *config.Config) error {
+	if m.measurement != nil {
+		return nil
+	}
+
+	mes, err := exporter.NewMeasurementForType(first.Type(), m.id, strconv.Itoa(first.Af()), cfg)
+	if err != nil {
+		return err
+	}
+
+	m.measurement = mes
+	return nil
+}
+
+func (m *polledMeasurement) newResults(atlasser ripeatlas.Atlaser) ([]*measurement.Result, error) {
+	m.mu.Lock()
+	first := m.measurement == nil
+	start := m.lastTimestamp - int(lateResultWindow.Seconds())
+	m.mu.Unlock()
+
+	var resultCh <-chan *measurement.Result
+	var err error
+	if first {
+		resultCh, err = atlasser.MeasurementLatest(ripeatlas.Params{"pk": m.id})
+	} else {
+		resultCh, err = atlasser.MeasurementResults(ripeatlas.Params{"pk": m.id, "start": int64(start)})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve measurement results for %s: %v", m.id, err)
+	}
+
+	res := []*measurement.Result{}
+	for r := range resultCh {
+		if r.ParseError != nil {
+			return nil, fmt.Errorf("failed parsing measurement result for %s: %v", m.id, r.ParseError)
+		}
+
+		res = append(res, r)
+	}
+
+	return res, nil
+}
+
+// current returns the measurement (nil if no results were retrieved yet)
+func (m *polledMeasurement) current() *exporter.Measurement {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.measurement
+}
@@ -0,0 +1,226 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package atlas
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DNS-OARC/ripeatlas"
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/DNS-OARC/ripeatlas/request"
+	"github.com/czerwonk/atlas_exporter/config"
+	"github.com/czerwonk/atlas_exporter/exporter"
+	"github.com/czerwonk/atlas_exporter/probe"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAtlaser serves the results published by a test like the Atlas API would
+type fakeAtlaser struct {
+	results      []*measurement.Result
+	latestCalls  int
+	resultStarts []int64
+	// block delays responses for results until closed (if set)
+	block chan struct{}
+	mu    sync.Mutex
+}
+
+func (a *fakeAtlaser) publish(res ...*measurement.Result) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.results = append(a.results, res...)
+}
+
+// calls returns the number of requests for results
+func (a *fakeAtlaser) calls() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.latestCalls + len(a.resultStarts)
+}
+
+func (a *fakeAtlaser) Measurements(p ripeatlas.Params) (<-chan *ripeatlas.Measurement, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (a *fakeAtlaser) MeasurementLatest(p ripeatlas.Params) (<-chan *measurement.Result, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.latestCalls++
+
+	latest := make(map[int]*measurement.Result)
+	for _, r := range a.results {
+		if l, found := latest[r.PrbId()]; !found || r.Timestamp() > l.Timestamp() {
+			latest[r.PrbId()] = r
+		}
+	}
+
+	ch := make(chan *measurement.Result, len(latest))
+	for _, r := range latest {
+		ch <- r
+	}
+	close(ch)
+
+	return ch, nil
+}
+
+func (a *fakeAtlaser) MeasurementResults(p ripeatlas.Params) (<-chan *measurement.Result, error) {
+	if a.block != nil {
+		<-a.block
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	start := p["start"].(int64)
+	a.resultStarts = append(a.resultStarts, start)
+
+	ch := make(chan *measurement.Result, len(a.results))
+	for _, r := range a.results {
+		if int64(r.Timestamp()) >= start {
+			ch <- r
+		}
+	}
+	close(ch)
+
+	return ch, nil
+}
+
+func (a *fakeAtlaser) Probes(p ripeatlas.Params) (<-chan *request.Probe, error) {
+	return nil, errors.New("not implemented")
+}
+
+func initTestCache(ids ...int) {
+	cache = probe.NewCache(time.Hour)
+	for _, id := range ids {
+		cache.Add(id, &probe.Probe{ID: id, Asn4: 64496})
+	}
+}
+
+func pingResult(t *testing.T, prbID, timestamp int) *measurement.Result {
+	var r measurement.Result
+	s := fmt.Sprintf(`{"type": "ping", "af": 4, "msm_id": 1, "prb_id": %d, "timestamp": %d, "result": [{"rtt": 1.5}]}`, prbID, timestamp)
+	err := json.Unmarshal([]byte(s), &r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &r
+}
+
+// rttCount returns the number of round trip times observed by the histogram of a ping measurement
+func rttCount(t *testing.T, m *exporter.Measurement) uint64 {
+	if m == nil {
+		return 0
+	}
+
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(m)
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var count uint64
+	for _, f := range families {
+		if f.GetName() != "atlas_ping_rtt_hist" {
+			continue
+		}
+
+		for _, metric := range f.GetMetric() {
+			count += metric.GetHistogram().GetSampleCount()
+		}
+	}
+
+	return count
+}
+
+func TestPolledMeasurementUpdate(t *testing.T) {
+	initTestCache(1, 2, 3)
+	cfg := &config.Config{}
+	atlasser := &fakeAtlaser{}
+	m := newPolledMeasurement("1")
+
+	atlasser.publish(pingResult(t, 1, 1000), pingResult(t, 1, 1200), pingResult(t, 2, 1200))
+	assert.NoError(t, m.update(atlasser, cfg, 1))
+	assert.Equal(t, 1, atlasser.latestCalls, "first update uses latest results")
+	assert.Empty(t, atlasser.resultStarts)
+	assert.Equal(t, uint64(2), rttCount(t, m.current()), "only latest result of each probe")
+
+	atlasser.publish(pingResult(t, 1, 1300), pingResult(t, 2, 1300), pingResult(t, 3, 1300))
+	assert.NoError(t, m.update(atlasser, cfg, 1))
+	assert.Equal(t, 1, atlasser.latestCalls)
+	assert.Equal(t, []int64{1200 - 600}, atlasser.resultStarts, "later updates retrieve results incrementally")
+	assert.Equal(t, uint64(5), rttCount(t, m.current()), "results of the same second are added")
+
+	assert.NoError(t, m.update(atlasser, cfg, 1))
+	assert.Equal(t, uint64(5), rttCount(t, m.current()), "results already seen are not added again")
+
+	atlasser.publish(pingResult(t, 1, 1400))
+	assert.NoError(t, m.update(atlasser, cfg, 1))
+	assert.Equal(t, uint64(6), rttCount(t, m.current()))
+
+	atlasser.publish(pingResult(t, 2, 1350), pingResult(t, 3, 1250))
+	assert.NoError(t, m.update(atlasser, cfg, 1))
+	assert.Equal(t, uint64(7), rttCount(t, m.current()), "late result newer than the last result of the probe is added")
+}
+
+func TestPolledMeasurementAdd(t *testing.T) {
+	initTestCache(1)
+	cfg := &config.Config{}
+	atlasser := &fakeAtlaser{}
+	m := newPolledMeasurement("1")
+
+	r := pingResult(t, 1, 1000)
+	assert.NoError(t, m.add(r, &probe.Probe{ID: 1, Asn4: 64496}, cfg))
+	assert.Equal(t, uint64(1), rttCount(t, m.current()))
+
+	atlasser.publish(r)
+	assert.NoError(t, m.update(atlasser, cfg, 1))
+	assert.Equal(t, 0, atlasser.latestCalls)
+	assert.Equal(t, uint64(1), rttCount(t, m.current()), "result added before is not polled again")
+
+	atlasser.publish(pingResult(t, 1, 1100))
+	assert.NoError(t, m.update(atlasser, cfg, 1))
+	assert.NoError(t, m.add(pingResult(t, 1, 1100), &probe.Probe{ID: 1, Asn4: 64496}, cfg))
+	assert.Equal(t, uint64(2), rttCount(t, m.current()), "result polled before is not added again")
+}
+
+func TestPolledMeasurementAddDuringUpdate(t *testing.T) {
+	initTestCache(1, 2)
+	cfg := &config.Config{}
+	atlasser := &fakeAtlaser{block: make(chan struct{})}
+	m := newPolledMeasurement("1")
+
+	assert.NoError(t, m.add(pingResult(t, 1, 1000), &probe.Probe{ID: 1, Asn4: 64496}, cfg))
+	atlasser.publish(pingResult(t, 2, 1100))
+
+	done := make(chan error)
+	go func() {
+		done <- m.update(atlasser, cfg, 1)
+	}()
+
+	added := make(chan error)
+	go func() {
+		added <- m.add(pingResult(t, 2, 1100), &probe.Probe{ID: 2, Asn4: 64496}, cfg)
+	}()
+
+	select {
+	case err := <-added:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("add blocked by update retrieving results")
+	}
+
+	close(atlasser.block)
+	assert.NoError(t, <-done)
+	assert.Equal(t, uint64(2), rttCount(t, m.current()), "result added while retrieving is not added again")
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package atlas
+
+import (
+	"context"
+	"time"
+
+	"github.com/DNS-OARC/ripeatlas"
+	"github.com/czerwonk/atlas_exporter/config"
+	"github.com/czerwonk/atlas_exporter/exporter"
+	log "github.com/sirupsen/logrus"
+)
+
+type pollingStrategy struct {
+	atlasser     ripeatlas.Atlaser
+	workers      uint
+	cfg          *config.Config
+	interval     time.Duration
+	measurements map[string]*polledMeasurement
+}
+
+// NewPollingStrategy returns a strategy polling the results of all configured measurements from Atlas API
+// in the background. Only results since the last poll are retrieved, scrapes are served from memory.
+func NewPollingStrategy(ctx context.Context, cfg *config.Config, workers uint, interval time.Duration) Strategy {
+	return newPollingStrategy(ctx, cfg, ripeatlas.Atlaser(ripeatlas.NewHttp()), workers, interval)
+}
+
+func newPollingStrategy(ctx context.Context, cfg *config.Config, atlasser ripeatlas.Atlaser, workers uint, interval time.Duration) *pollingStrategy {
+	s := &pollingStrategy{
+		atlasser:     atlasser,
+		workers:      workers,
+		cfg:          cfg,
+		interval:     interval,
+		measurements: make(map[string]*polledMeasurement),
+	}
+
+	for _, id := range cfg.MeasurementIDs() {
+		m := newPolledMeasurement(id)
+		s.measurements[id] = m
+		go s.poll(ctx, m)
+	}
+
+	return s
+}
+
+func (s *pollingStrategy) poll(ctx context.Context, m *polledMeasurement) {
+	for {
+		err := m.update(s.atlasser, s.cfg, s.workers)
+		if err != nil {
+			log.Error(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.interval):
+			continue
+		}
+	}
+}
+
+func (s *pollingStrategy) MeasurementResults(ctx context.Context, ids []string) ([]*exporter.Measurement, error) {
+	result := make([]*exporter.Measurement, 0)
+	for _, id := range ids {
+		m, found := s.measurements[id]
+		if !found {
+			continue
+		}
+
+		if mes := m.current(); mes != nil {
+			result = append(result, mes)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package atlas
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/czerwonk/atlas_exporter/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPollingStrategyServesFromMemory(t *testing.T) {
+	initTestCache(1, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	atlasser := &fakeAtlaser{}
+	atlasser.publish(pingResult(t, 1, 1000), pingResult(t, 2, 1000))
+
+	cfg := &config.Config{Measurements: []config.Measurement{{ID: "1"}}}
+	s := newPollingStrategy(ctx, cfg, atlasser, 1, time.Hour)
+
+	assert.Eventually(t, func() bool {
+		res, err := s.MeasurementResults(ctx, []string{"1"})
+		return err == nil && len(res) == 1 && rttCount(t, res[0]) == 2
+	}, time.Second, 5*time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		res, err := s.MeasurementResults(ctx, []string{"1", "2"})
+		assert.NoError(t, err)
+		assert.Len(t, res, 1, "unknown measurements are ignored")
+	}
+	assert.Equal(t, 1, atlasser.calls(), "scrapes do not request the API")
+}
+
+func TestPollingStrategyStopsOnCancel(t *testing.T) {
+	initTestCache(1)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	atlasser := &fakeAtlaser{}
+	atlasser.publish(pingResult(t, 1, 1000))
+
+	cfg := &config.Config{Measurements: []config.Measurement{{ID: "1"}}}
+	newPollingStrategy(ctx, cfg, atlasser, 1, 5*time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		return atlasser.calls() >= 3
+	}, time.Second, time.Millisecond, "results are polled in the interval")
+
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+	calls := atlasser.calls()
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, calls, atlasser.calls(), "no polls after the context was cancelled")
+}
@@ -4,33 +4,35 @@ package atlas
 
 import (
 	"context"
-	"strconv"
 	"sync"
 
 	"github.com/czerwonk/atlas_exporter/exporter"
 
 	"github.com/DNS-OARC/ripeatlas"
-	"github.com/DNS-OARC/ripeatlas/measurement"
 	"github.com/czerwonk/atlas_exporter/config"
 	log "github.com/sirupsen/logrus"
 )
 
 type requestStrategy struct {
-	atlasser ripeatlas.Atlaser
-	workers  uint
-	cfg      *config.Config
+	atlasser     ripeatlas.Atlaser
+	workers      uint
+	cfg          *config.Config
+	measurements map[string]*polledMeasurement
+	mu           sync.Mutex
 }
 
-// NewRequestStrategy returns an strategy to retrieve data from Atlas API using requests
+// NewRequestStrategy returns an strategy to retrieve data from Atlas API using requests.
+// Measurements are kept between calls, so only results since the last call are retrieved and added.
 func NewRequestStrategy(cfg *config.Config, workers uint) Strategy {
-	return requestStrategy{
-		atlasser: ripeatlas.Atlaser(ripeatlas.NewHttp()),
-		cfg:      cfg,
-		workers:  workers,
+	return &requestStrategy{
+		atlasser:     ripeatlas.Atlaser(ripeatlas.NewHttp()),
+		cfg:          cfg,
+		workers:      workers,
+		measurements: make(map[string]*polledMeasurement),
 	}
 }
 
-func (s requestStrategy) MeasurementResults(ctx context.Context, ids []string) ([]*exporter.Measurement, error) {
+func (s *requestStrategy) MeasurementResults(ctx context.Context, ids []string) ([]*exporter.Measurement, error) {
 	ch := make(chan *exporter.Measurement)
 
 	wg := sync.WaitGroup{}
@@ -63,42 +65,27 @@ func (s requestStrategy) MeasurementResults(ctx context.Context, ids []string) (
 func (s *requestStrategy) getMeasurementForID(ctx context.Context, id string, ch chan<- *exporter.Measurement, wg *sync.WaitGroup) {
 	defer wg.Done()
 
-	resultCh, err := s.atlasser.MeasurementLatest(ripeatlas.Params{"pk": id})
-	if err != nil {
-		log.Errorf("could not retrieve measurement results for %s: %v", id, err)
-		return
-	}
-
-	res := []*measurement.Result{}
-	for m := range resultCh {
-		if m.ParseError != nil {
-			log.Errorf("failed parsing measurement result for %s: %v", id, m.ParseError)
-			return
-		}
-
-		res = append(res, m)
-	}
-
-	if len(res) == 0 {
-		return
-	}
-
-	first := res[0]
-	mes, err := measurementForType(first.Type(), id, strconv.Itoa(first.Af()), s.cfg)
+	m := s.polledMeasurement(id)
+	err := m.update(s.atlasser, s.cfg, s.workers)
 	if err != nil {
 		log.Errorln(err)
 		return
 	}
 
-	probes, err := probesForResults(res, s.workers)
-	if err != nil {
-		log.Errorln(err)
-		return
+	if mes := m.current(); mes != nil {
+		ch <- mes
 	}
+}
+
+func (s *requestStrategy) polledMeasurement(id string) *polledMeasurement {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	for _, r := range res {
-		mes.Add(r, probes[r.PrbId()])
+	m, found := s.measurements[id]
+	if !found {
+		m = newPolledMeasurement(id)
+		s.measurements[id] = m
 	}
 
-	ch <- mes
+	return m
 }
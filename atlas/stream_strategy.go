@@ -11,6 +11,7 @@ import (
 	"github.com/czerwonk/atlas_exporter/exporter"
 	"github.com/czerwonk/atlas_exporter/probe"
 
+	"github.com/DNS-OARC/ripeatlas"
 	"github.com/DNS-OARC/ripeatlas/measurement"
 	"github.com/czerwonk/atlas_exporter/config"
 	log "github.com/sirupsen/logrus"
@@ -37,10 +38,10 @@ func NewStreamingStrategy(ctx context.Context, cfg *config.Config, bufferSize ui
 
 func (s *streamingStrategy) start(ctx context.Context, measurements []config.Measurement, bufferSize uint) {
 	resultCh := make(chan *measurement.Result, int(bufferSize))
-	resetCh := make(chan *config.Measurement)
 
 	for _, m := range measurements {
 		w := &streamStrategyWorker{
+			atlasser:    ripeatlas.NewStream(),
 			resultCh:    resultCh,
 			measurement: m,
 			timeout:     s.timeoutForMeasurement(m),
@@ -48,27 +49,15 @@ func (s *streamingStrategy) start(ctx context.Context, measurements []config.Mea
 		go w.run(ctx)
 	}
 
-	go s.processMeasurementResults(resultCh, resetCh)
+	go s.processMeasurementResults(resultCh)
 }
 
-func (s *streamingStrategy) processMeasurementResults(resultCh chan *measurement.Result, resetCh chan *config.Measurement) {
-	for {
-		select {
-		case r := <-resultCh:
-			s.processMeasurementResult(r)
-		case m := <-resetCh:
-			s.clearResults(m.ID)
-		}
+func (s *streamingStrategy) processMeasurementResults(resultCh chan *measurement.Result) {
+	for r := range resultCh {
+		s.processMeasurementResult(r)
 	}
 }
 
-func (s *streamingStrategy) clearResults(id string) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	delete(s.measurements, id)
-}
-
 func (s *streamingStrategy) timeoutForMeasurement(m config.Measurement) time.Duration {
 	if m.Timeout == 0 {
 		return s.defaultTimeout
@@ -98,7 +87,7 @@ func (s *streamingStrategy) add(m *measurement.Result, probe *probe.Probe) {
 	mes, found := s.measurements[msm]
 	if !found {
 		var err error
-		mes, err = measurementForType(m.Type(), msm, strconv.Itoa(m.Af()), s.cfg)
+		mes, err = exporter.NewMeasurementForType(m.Type(), msm, strconv.Itoa(m.Af()), s.cfg)
 		if err != nil {
 			log.Error(err)
 			return
@@ -17,23 +17,17 @@ import (
 const connectionRetryInterval = 30 * time.Second
 
 type streamStrategyWorker struct {
+	atlasser    ripeatlas.Atlaser
 	resultCh    chan<- *measurement.Result
-	resetCh     chan<- *config.Measurement
 	measurement config.Measurement
 	timeout     time.Duration
 }
 
+// run streams the results of the measurement until the context is cancelled. Results and histograms of the
+// measurement are kept on reconnects, since the measurement itself does not change.
 func (w *streamStrategyWorker) run(ctx context.Context) error {
 	for {
-		ch, err := w.subscribe()
-		if err != nil {
-			log.Error(err)
-		} else {
-			log.Infof("Subscribed to results of measurement #%s", w.measurement.ID)
-			w.listenForResults(ctx, w.timeout, ch)
-		}
-
-		w.resetCh <- &w.measurement
+		w.stream(ctx)
 
 		select {
 		case <-ctx.Done():
@@ -44,15 +38,25 @@ func (w *streamStrategyWorker) run(ctx context.Context) error {
 	}
 }
 
-func (w *streamStrategyWorker) subscribe() (<-chan *measurement.Result, error) {
-	stream := ripeatlas.NewStream()
+// stream subscribes to the results of the measurement and returns the number of results received until disconnected
+func (w *streamStrategyWorker) stream(ctx context.Context) int {
+	ch, err := w.subscribe()
+	if err != nil {
+		log.Error(err)
+		return 0
+	}
+
+	log.Infof("Subscribed to results of measurement #%s", w.measurement.ID)
+	return w.listenForResults(ctx, w.timeout, ch)
+}
 
+func (w *streamStrategyWorker) subscribe() (<-chan *measurement.Result, error) {
 	msm, err := strconv.Atoi(w.measurement.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	ch, err := stream.MeasurementResults(ripeatlas.Params{
+	ch, err := w.atlasser.MeasurementResults(ripeatlas.Params{
 		"msm": msm,
 	})
 	if err != nil {
@@ -62,29 +66,42 @@ func (w *streamStrategyWorker) subscribe() (<-chan *measurement.Result, error) {
 	return ch, nil
 }
 
-func (w *streamStrategyWorker) listenForResults(ctx context.Context, timeout time.Duration, ch <-chan *measurement.Result) {
+// listenForResults forwards results until the connection is closed or times out and returns the number of results forwarded
+func (w *streamStrategyWorker) listenForResults(ctx context.Context, timeout time.Duration, ch <-chan *measurement.Result) (received int) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
 	for {
 		select {
-		case m := <-ch:
+		case m, ok := <-ch:
+			if !ok {
+				log.Errorf("Connection closed for measurement #%s. Trying to reconnect.", w.measurement.ID)
+				return received
+			}
+
 			if m == nil {
 				continue
 			}
 
+			timer.Reset(timeout)
+
 			if m.ParseError != nil {
 				log.Error(m.ParseError)
-			}
 
-			if m.ParseError != nil && strings.HasPrefix(m.ParseError.Error(), "c.On(disconnect)") {
-				log.Error(m.ParseError)
-				return
+				if strings.HasPrefix(m.ParseError.Error(), "c.On(disconnect)") {
+					return received
+				}
+
+				continue
 			}
 
 			w.resultCh <- m
-		case <-time.After(timeout):
+			received++
+		case <-timer.C:
 			log.Errorf("Timeout reached for measurement #%s. Trying to reconnect.", w.measurement.ID)
-			return
+			return received
 		case <-ctx.Done():
-			return
+			return received
 		}
 	}
 }
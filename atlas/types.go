@@ -0,0 +1,14 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package atlas
+
+// built-in measurement types registering their factories in the exporter package
+import (
+	_ "github.com/czerwonk/atlas_exporter/dns"
+	_ "github.com/czerwonk/atlas_exporter/http"
+	_ "github.com/czerwonk/atlas_exporter/ntp"
+	_ "github.com/czerwonk/atlas_exporter/ping"
+	_ "github.com/czerwonk/atlas_exporter/sslcert"
+	_ "github.com/czerwonk/atlas_exporter/traceroute"
+	_ "github.com/czerwonk/atlas_exporter/wifi"
+)
@@ -3,9 +3,13 @@
 package config
 
 import (
+	"crypto/x509"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
+	"os"
+	"regexp"
 	"time"
 
 	yaml "gopkg.in/yaml.v2"
@@ -17,25 +21,133 @@ type Config struct {
 	Measurements         []Measurement    `yaml:"measurements"`
 	HistogramBuckets     HistogramBuckets `yaml:"histogram_buckets"`
 	FilterInvalidResults bool             `yaml:"filter_invalid_results"`
+	Traceroute           Traceroute       `yaml:"traceroute,omitempty"`
+	// HistogramPartition defines probe attributes used to partition histograms
+	HistogramPartition HistogramPartition `yaml:"histogram_partition,omitempty"`
+}
+
+// HistogramPartition defines the probe attributes histograms are partitioned by
+type HistogramPartition struct {
+	// By are the probe attributes added as labels to histograms (country_code, continent, asn, tag)
+	By []string `yaml:"by"`
+	// Tags are the probe tags (slugs) used for partitioning by tag. The first tag assigned to a probe is used.
+	Tags []string `yaml:"tags,omitempty"`
+}
+
+// Traceroute defines options for traceroute measurements
+type Traceroute struct {
+	// ICMPExtensions enables parsing of ICMP extension objects (e.g. MPLS label stacks, RFC 4950)
+	ICMPExtensions bool `yaml:"icmp_extensions"`
 }
 
 // HistogramBuckets defines buckets for several histograms
 type HistogramBuckets struct {
-	DNS        RttHistogramBucket `yaml:"dns,omitempty"`
-	HTTP       RttHistogramBucket `yaml:"http,omitempty"`
-	Ping       RttHistogramBucket `yaml:"ping,omitempty"`
-	Traceroute RttHistogramBucket `yaml:"traceroute,omitempty"`
+	DNS        RttHistogramBucket  `yaml:"dns,omitempty"`
+	HTTP       HTTPHistogramBucket `yaml:"http,omitempty"`
+	NTP        NTPHistogramBucket  `yaml:"ntp,omitempty"`
+	Ping       RttHistogramBucket  `yaml:"ping,omitempty"`
+	Traceroute RttHistogramBucket  `yaml:"traceroute,omitempty"`
 }
 
 // RttHistogramBucket defines buckets for RTT histograms
 type RttHistogramBucket struct {
 	Rtt []float64 `yaml:"rtt"`
+	// Native enables native histograms for all histograms of the type
+	Native NativeHistogram `yaml:"native,omitempty"`
+}
+
+// NativeHistogram defines options for native (sparse) histograms. Native histograms are exposed alongside the
+// classic buckets and only served when the protobuf exposition format is negotiated by the scraper.
+type NativeHistogram struct {
+	// BucketFactor is the maximum growth factor between two consecutive buckets (disabled if not set)
+	BucketFactor float64 `yaml:"bucket_factor,omitempty"`
+	// MaxBuckets limits the number of buckets, the resolution is reduced when exceeded (unlimited if not set)
+	MaxBuckets uint32 `yaml:"max_buckets,omitempty"`
+	// ZeroThreshold is the width of the zero bucket (default of the Prometheus client if not set)
+	ZeroThreshold float64 `yaml:"zero_threshold,omitempty"`
+	// Disabled turns off native histograms for a measurement even if they are enabled for the type
+	Disabled bool `yaml:"disabled,omitempty"`
+}
+
+// Enabled returns true if native histograms should be exposed
+func (n *NativeHistogram) Enabled() bool {
+	return !n.Disabled && n.BucketFactor > 1
+}
+
+// HTTPHistogramBucket defines buckets for HTTP histograms
+type HTTPHistogramBucket struct {
+	RttHistogramBucket `yaml:",inline"`
+	// Timing defines buckets for the histograms of the request phases (time to resolve, connect and first byte)
+	Timing []float64 `yaml:"timing,omitempty"`
+}
+
+// NTPHistogramBucket defines buckets for NTP histograms (in seconds)
+type NTPHistogramBucket struct {
+	RttHistogramBucket `yaml:",inline"`
+	Offset             []float64 `yaml:"offset,omitempty"`
 }
 
 // Measurement represents config options for one measurement
 type Measurement struct {
 	ID      string        `yaml:"id"`
 	Timeout time.Duration `yaml:"timeout,omitempty"`
+	DNS     DNS           `yaml:"dns,omitempty"`
+	HTTP    HTTP          `yaml:"http,omitempty"`
+	SSLCert SSLCert       `yaml:"sslcert,omitempty"`
+	// HistogramBuckets overrides the buckets defined for the type of the measurement (only buckets set are overridden)
+	HistogramBuckets HistogramBuckets `yaml:"histogram_buckets,omitempty"`
+	// Summary enables a sliding window summary of round trip times
+	Summary Summary `yaml:"summary,omitempty"`
+}
+
+// Summary defines options for sliding window summaries
+type Summary struct {
+	// MaxAge is the duration observations are kept for (disabled if not set)
+	MaxAge time.Duration `yaml:"max_age"`
+	// Objectives are the quantiles to calculate (0.5, 0.9 and 0.99 if not set)
+	Objectives []float64 `yaml:"objectives,omitempty"`
+}
+
+// Enabled returns true if the summary should be exposed
+func (s *Summary) Enabled() bool {
+	return s.MaxAge > 0
+}
+
+// DNS defines options for DNS measurements
+type DNS struct {
+	ExpectedAnswers ExpectedAnswers `yaml:"expected_answers,omitempty"`
+}
+
+// HTTP defines options for HTTP measurements
+type HTTP struct {
+	// AcceptableStatusCodes are the status codes considered as success (2xx and 3xx if not set)
+	AcceptableStatusCodes []int `yaml:"acceptable_status_codes,omitempty"`
+}
+
+// SSLCert defines options for SSL/TLS certificate measurements
+type SSLCert struct {
+	// CAFile is the path to a PEM encoded CA bundle used to verify certificates (system trust store if not set)
+	CAFile string `yaml:"ca_file,omitempty"`
+	// Hostname certificates are verified against (target name of the measurement if not set)
+	Hostname string `yaml:"hostname,omitempty"`
+}
+
+// ExpectedAnswers defines the records expected in the answer section of DNS responses.
+// Records of types without expected values are not checked.
+type ExpectedAnswers struct {
+	// Addresses expected in A/AAAA records
+	Addresses []string `yaml:"addresses,omitempty"`
+	// CNAMEs expected as target of CNAME records
+	CNAMEs []string `yaml:"cnames,omitempty"`
+	// TXT strings expected in TXT records (multiple strings of a record are concatenated)
+	TXT []string `yaml:"txt,omitempty"`
+	// TXTRegex are regular expressions TXT records are matched against
+	TXTRegex []string `yaml:"txt_regex,omitempty"`
+}
+
+// Empty returns true if no expected answer is defined
+func (e *ExpectedAnswers) Empty() bool {
+	return len(e.Addresses) == 0 && len(e.CNAMEs) == 0 && len(e.TXT) == 0 && len(e.TXTRegex) == 0
 }
 
 // MeasurementIDs represents all IDs of configured measurements
@@ -48,6 +160,64 @@ func (c *Config) MeasurementIDs() []string {
 	return ids
 }
 
+// MeasurementByID returns the config options for a measurement (false if not configured)
+func (c *Config) MeasurementByID(id string) (Measurement, bool) {
+	for _, m := range c.Measurements {
+		if m.ID == id {
+			return m, true
+		}
+	}
+
+	return Measurement{ID: id}, false
+}
+
+// HistogramBucketsForMeasurement returns the histogram buckets for a measurement (type defaults merged with the
+// buckets defined for the measurement)
+func (c *Config) HistogramBucketsForMeasurement(id string) HistogramBuckets {
+	m, _ := c.MeasurementByID(id)
+	return c.HistogramBuckets.merge(m.HistogramBuckets)
+}
+
+func (b HistogramBuckets) merge(o HistogramBuckets) HistogramBuckets {
+	b.DNS = b.DNS.merge(o.DNS)
+	b.HTTP = b.HTTP.merge(o.HTTP)
+	b.NTP = b.NTP.merge(o.NTP)
+	b.Ping = b.Ping.merge(o.Ping)
+	b.Traceroute = b.Traceroute.merge(o.Traceroute)
+
+	return b
+}
+
+func (b RttHistogramBucket) merge(o RttHistogramBucket) RttHistogramBucket {
+	if o.Rtt != nil {
+		b.Rtt = o.Rtt
+	}
+
+	if o.Native.Enabled() || o.Native.Disabled {
+		b.Native = o.Native
+	}
+
+	return b
+}
+
+func (b HTTPHistogramBucket) merge(o HTTPHistogramBucket) HTTPHistogramBucket {
+	b.RttHistogramBucket = b.RttHistogramBucket.merge(o.RttHistogramBucket)
+	if o.Timing != nil {
+		b.Timing = o.Timing
+	}
+
+	return b
+}
+
+func (b NTPHistogramBucket) merge(o NTPHistogramBucket) NTPHistogramBucket {
+	b.RttHistogramBucket = b.RttHistogramBucket.merge(o.RttHistogramBucket)
+	if o.Offset != nil {
+		b.Offset = o.Offset
+	}
+
+	return b
+}
+
 // Load loads a config from a reader
 func Load(r io.Reader) (*Config, error) {
 	b, err := ioutil.ReadAll(r)
@@ -67,5 +237,100 @@ func Load(r io.Reader) (*Config, error) {
 		return nil, fmt.Errorf("could not parse config: %v", err)
 	}
 
+	err = c.validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %v", err)
+	}
+
 	return c, err
 }
+
+func (c *Config) validate() error {
+	for _, b := range c.HistogramPartition.By {
+		switch b {
+		case "country_code", "continent", "asn":
+		case "tag":
+			if len(c.HistogramPartition.Tags) == 0 {
+				return fmt.Errorf("histogram partition by tag requires at least one tag")
+			}
+		default:
+			return fmt.Errorf("unknown probe attribute for histogram partition: %s", b)
+		}
+	}
+
+	err := c.HistogramBuckets.validate()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range c.Measurements {
+		err = m.HistogramBuckets.validate()
+		if err != nil {
+			return fmt.Errorf("measurement %s: %v", m.ID, err)
+		}
+
+		for _, q := range m.Summary.Objectives {
+			if q <= 0 || q >= 1 {
+				return fmt.Errorf("measurement %s: summary objective %v is not between 0 and 1", m.ID, q)
+			}
+		}
+
+		for _, a := range m.DNS.ExpectedAnswers.Addresses {
+			if net.ParseIP(a) == nil {
+				return fmt.Errorf("measurement %s: invalid expected address %q", m.ID, a)
+			}
+		}
+
+		for _, r := range m.DNS.ExpectedAnswers.TXTRegex {
+			_, err := regexp.Compile(r)
+			if err != nil {
+				return fmt.Errorf("measurement %s: invalid TXT regex %q: %v", m.ID, r, err)
+			}
+		}
+
+		err = m.SSLCert.validate()
+		if err != nil {
+			return fmt.Errorf("measurement %s: %v", m.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *SSLCert) validate() error {
+	if len(s.CAFile) == 0 {
+		return nil
+	}
+
+	b, err := os.ReadFile(s.CAFile)
+	if err != nil {
+		return fmt.Errorf("could not read CA file: %v", err)
+	}
+
+	if !x509.NewCertPool().AppendCertsFromPEM(b) {
+		return fmt.Errorf("no certificates found in CA file %s", s.CAFile)
+	}
+
+	return nil
+}
+
+func (b *HistogramBuckets) validate() error {
+	native := map[string]NativeHistogram{
+		"dns":        b.DNS.Native,
+		"http":       b.HTTP.Native,
+		"ntp":        b.NTP.Native,
+		"ping":       b.Ping.Native,
+		"traceroute": b.Traceroute.Native,
+	}
+	for t, n := range native {
+		if n.BucketFactor != 0 && n.BucketFactor <= 1 {
+			return fmt.Errorf("%s: native histogram bucket factor must be greater than 1", t)
+		}
+
+		if n.ZeroThreshold < 0 {
+			return fmt.Errorf("%s: native histogram zero threshold must not be negative", t)
+		}
+	}
+
+	return nil
+}
@@ -3,6 +3,15 @@
 package config
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -48,6 +57,10 @@ histogram_buckets:
     rtt: [ 1.0, 2.0 ]
   http: 
     rtt: [ 3.0, 4.0 ]
+    timing: [ 9.0, 10.0 ]
+  ntp:
+    rtt: [ 0.01, 0.1 ]
+    offset: [ -0.1, 0.1 ]
   ping: 
     rtt: [ 5.0, 6.0 ]
   traceroute: 
@@ -60,8 +73,17 @@ histogram_buckets:
 					DNS: RttHistogramBucket{
 						Rtt: []float64{1, 2},
 					},
-					HTTP: RttHistogramBucket{
-						Rtt: []float64{3, 4},
+					HTTP: HTTPHistogramBucket{
+						RttHistogramBucket: RttHistogramBucket{
+							Rtt: []float64{3, 4},
+						},
+						Timing: []float64{9, 10},
+					},
+					NTP: NTPHistogramBucket{
+						RttHistogramBucket: RttHistogramBucket{
+							Rtt: []float64{0.01, 0.1},
+						},
+						Offset: []float64{-0.1, 0.1},
 					},
 					Ping: RttHistogramBucket{
 						Rtt: []float64{5, 6},
@@ -91,6 +113,243 @@ measurements:
 				FilterInvalidResults: true,
 			},
 		},
+		{
+			name: "valid config with expected DNS answers",
+			value: `
+measurements:
+  - id: 123
+    dns:
+      expected_answers:
+        addresses: [ 192.0.2.1, 2001:db8::1 ]
+        cnames: [ cdn.example.com. ]
+        txt: [ v=spf1 -all ]
+        txt_regex: [ "^google-site-verification=" ]`,
+			expected: Config{
+				Measurements: []Measurement{
+					{
+						ID: "123",
+						DNS: DNS{
+							ExpectedAnswers: ExpectedAnswers{
+								Addresses: []string{"192.0.2.1", "2001:db8::1"},
+								CNAMEs:    []string{"cdn.example.com."},
+								TXT:       []string{"v=spf1 -all"},
+								TXTRegex:  []string{"^google-site-verification="},
+							},
+						},
+					},
+				},
+				FilterInvalidResults: true,
+			},
+		},
+		{
+			name: "valid config with acceptable HTTP status codes",
+			value: `
+measurements:
+  - id: 123
+    http:
+      acceptable_status_codes: [ 200, 204 ]`,
+			expected: Config{
+				Measurements: []Measurement{
+					{
+						ID: "123",
+						HTTP: HTTP{
+							AcceptableStatusCodes: []int{200, 204},
+						},
+					},
+				},
+				FilterInvalidResults: true,
+			},
+		},
+		{
+			name: "valid config with sslcert hostname",
+			value: `
+measurements:
+  - id: 123
+    sslcert:
+      hostname: www.example.com`,
+			expected: Config{
+				Measurements: []Measurement{
+					{
+						ID: "123",
+						SSLCert: SSLCert{
+							Hostname: "www.example.com",
+						},
+					},
+				},
+				FilterInvalidResults: true,
+			},
+		},
+		{
+			name: "missing sslcert CA file",
+			value: `
+measurements:
+  - id: 123
+    sslcert:
+      ca_file: /nonexistent/internal-ca.pem`,
+			wantsFail: true,
+		},
+		{
+			name: "invalid expected address",
+			value: `
+measurements:
+  - id: 123
+    dns:
+      expected_answers:
+        addresses: [ "192.0.2.1", "192.0.2.256" ]`,
+			wantsFail: true,
+		},
+		{
+			name: "invalid TXT regex",
+			value: `
+measurements:
+  - id: 123
+    dns:
+      expected_answers:
+        txt_regex: [ "(" ]`,
+			wantsFail: true,
+		},
+		{
+			name: "valid config with traceroute ICMP extensions",
+			value: `
+traceroute:
+  icmp_extensions: true`,
+			expected: Config{
+				FilterInvalidResults: true,
+				Traceroute: Traceroute{
+					ICMPExtensions: true,
+				},
+			},
+		},
+		{
+			name: "valid config with native histograms",
+			value: `
+histogram_buckets:
+  ping:
+    rtt: [ 5.0, 6.0 ]
+    native:
+      bucket_factor: 1.1
+      max_buckets: 100
+      zero_threshold: 0.001`,
+			expected: Config{
+				HistogramBuckets: HistogramBuckets{
+					Ping: RttHistogramBucket{
+						Rtt: []float64{5, 6},
+						Native: NativeHistogram{
+							BucketFactor:  1.1,
+							MaxBuckets:    100,
+							ZeroThreshold: 0.001,
+						},
+					},
+				},
+				FilterInvalidResults: true,
+			},
+		},
+		{
+			name: "valid config with native histograms disabled for a measurement",
+			value: `
+measurements:
+  - id: 123
+    histogram_buckets:
+      ping:
+        native:
+          disabled: true`,
+			expected: Config{
+				Measurements: []Measurement{
+					{
+						ID: "123",
+						HistogramBuckets: HistogramBuckets{
+							Ping: RttHistogramBucket{
+								Native: NativeHistogram{Disabled: true},
+							},
+						},
+					},
+				},
+				FilterInvalidResults: true,
+			},
+		},
+		{
+			name: "invalid native histogram bucket factor",
+			value: `
+histogram_buckets:
+  dns:
+    native:
+      bucket_factor: 0.5`,
+			wantsFail: true,
+		},
+		{
+			name: "valid config with histogram partition",
+			value: `
+histogram_partition:
+  by: [ continent, tag ]
+  tags: [ datacentre, home ]`,
+			expected: Config{
+				HistogramPartition: HistogramPartition{
+					By:   []string{"continent", "tag"},
+					Tags: []string{"datacentre", "home"},
+				},
+				FilterInvalidResults: true,
+			},
+		},
+		{
+			name: "invalid histogram partition",
+			value: `
+histogram_partition:
+  by: [ city ]`,
+			wantsFail: true,
+		},
+		{
+			name: "valid config with histogram buckets of measurement",
+			value: `
+measurements:
+  - id: 123
+    histogram_buckets:
+      ping:
+        rtt: [ 1.0, 5.0 ]`,
+			expected: Config{
+				Measurements: []Measurement{
+					{
+						ID: "123",
+						HistogramBuckets: HistogramBuckets{
+							Ping: RttHistogramBucket{
+								Rtt: []float64{1, 5},
+							},
+						},
+					},
+				},
+				FilterInvalidResults: true,
+			},
+		},
+		{
+			name: "valid config with summary",
+			value: `
+measurements:
+  - id: 123
+    summary:
+      max_age: 1h
+      objectives: [ 0.5, 0.95 ]`,
+			expected: Config{
+				Measurements: []Measurement{
+					{
+						ID: "123",
+						Summary: Summary{
+							MaxAge:     time.Hour,
+							Objectives: []float64{0.5, 0.95},
+						},
+					},
+				},
+				FilterInvalidResults: true,
+			},
+		},
+		{
+			name: "invalid summary objective",
+			value: `
+measurements:
+  - id: 123
+    summary:
+      max_age: 1h
+      objectives: [ 99 ]`,
+			wantsFail: true,
+		},
 		{
 			name: "valid config with filter override",
 			value: `
@@ -122,3 +381,126 @@ filter_invalid_results: false`,
 		})
 	}
 }
+
+func TestLoadSSLCertCAFile(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	b, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	err = os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: b}), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invalidFile := filepath.Join(dir, "invalid.pem")
+	err = os.WriteFile(invalidFile, []byte("not a certificate"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		caFile    string
+		wantsFail bool
+	}{
+		{
+			name:   "valid CA file",
+			caFile: caFile,
+		},
+		{
+			name:      "no certificates in CA file",
+			caFile:    invalidFile,
+			wantsFail: true,
+		},
+		{
+			name:      "missing CA file",
+			caFile:    filepath.Join(dir, "missing.pem"),
+			wantsFail: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(te *testing.T) {
+			c, err := Load(strings.NewReader(`
+measurements:
+  - id: 123
+    sslcert:
+      ca_file: ` + test.caFile))
+			if test.wantsFail {
+				assert.Error(te, err)
+				return
+			}
+
+			if assert.NoError(te, err) {
+				assert.Equal(te, test.caFile, c.Measurements[0].SSLCert.CAFile)
+			}
+		})
+	}
+}
+
+func TestHistogramBucketsForMeasurement(t *testing.T) {
+	c := &Config{
+		HistogramBuckets: HistogramBuckets{
+			HTTP: HTTPHistogramBucket{
+				RttHistogramBucket: RttHistogramBucket{
+					Rtt: []float64{100, 200},
+				},
+				Timing: []float64{10, 20},
+			},
+			Ping: RttHistogramBucket{
+				Rtt:    []float64{50, 100},
+				Native: NativeHistogram{BucketFactor: 1.1},
+			},
+		},
+		Measurements: []Measurement{
+			{
+				ID: "123",
+				HistogramBuckets: HistogramBuckets{
+					HTTP: HTTPHistogramBucket{
+						Timing: []float64{1, 2},
+					},
+					Ping: RttHistogramBucket{
+						Rtt: []float64{1, 5},
+					},
+				},
+			},
+			{
+				ID: "789",
+				HistogramBuckets: HistogramBuckets{
+					Ping: RttHistogramBucket{
+						Native: NativeHistogram{Disabled: true},
+					},
+				},
+			},
+		},
+	}
+
+	b := c.HistogramBucketsForMeasurement("123")
+	assert.Equal(t, []float64{1, 5}, b.Ping.Rtt)
+	assert.Equal(t, 1.1, b.Ping.Native.BucketFactor)
+	assert.Equal(t, []float64{100, 200}, b.HTTP.Rtt)
+	assert.Equal(t, []float64{1, 2}, b.HTTP.Timing)
+	assert.True(t, b.Ping.Native.Enabled())
+
+	b = c.HistogramBucketsForMeasurement("789")
+	assert.Equal(t, []float64{50, 100}, b.Ping.Rtt)
+	assert.False(t, b.Ping.Native.Enabled())
+
+	assert.Equal(t, c.HistogramBuckets, c.HistogramBucketsForMeasurement("456"))
+}
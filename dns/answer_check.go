@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package dns
+
+import (
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/czerwonk/atlas_exporter/config"
+	mdns "github.com/miekg/dns"
+	log "github.com/sirupsen/logrus"
+)
+
+// answerChecker checks records in the answer section against expected values
+type answerChecker struct {
+	addresses map[string]bool
+	cnames    map[string]bool
+	txt       map[string]bool
+	txtRegex  []*regexp.Regexp
+}
+
+func newAnswerChecker(expected config.ExpectedAnswers) *answerChecker {
+	if expected.Empty() {
+		return nil
+	}
+
+	c := &answerChecker{
+		addresses: make(map[string]bool),
+		cnames:    make(map[string]bool),
+		txt:       make(map[string]bool),
+		txtRegex:  make([]*regexp.Regexp, 0),
+	}
+
+	for _, a := range expected.Addresses {
+		ip := net.ParseIP(a)
+		if ip == nil {
+			log.Errorf("invalid expected address %s", a)
+			continue
+		}
+
+		c.addresses[ip.String()] = true
+	}
+
+	for _, n := range expected.CNAMEs {
+		c.cnames[normalizeName(n)] = true
+	}
+
+	for _, t := range expected.TXT {
+		c.txt[t] = true
+	}
+
+	for _, r := range expected.TXTRegex {
+		re, err := regexp.Compile(r)
+		if err != nil {
+			log.Errorf("invalid TXT regex %s: %v", r, err)
+			continue
+		}
+
+		c.txtRegex = append(c.txtRegex, re)
+	}
+
+	return c
+}
+
+// check returns the number of records checked and how many of them were not expected
+func (c *answerChecker) check(msg *mdns.Msg) (checked, unexpected int) {
+	for _, rr := range msg.Answer {
+		match, ok := c.matches(rr)
+		if !ok {
+			continue
+		}
+
+		checked++
+		if !match {
+			unexpected++
+		}
+	}
+
+	return checked, unexpected
+}
+
+// matches returns whether a record matches the expected values (ok is false if records of this type are not checked)
+func (c *answerChecker) matches(rr mdns.RR) (match bool, ok bool) {
+	switch r := rr.(type) {
+	case *mdns.A:
+		return c.addresses[r.A.String()], len(c.addresses) > 0
+	case *mdns.AAAA:
+		return c.addresses[r.AAAA.String()], len(c.addresses) > 0
+	case *mdns.CNAME:
+		return c.cnames[normalizeName(r.Target)], len(c.cnames) > 0
+	case *mdns.TXT:
+		return c.matchesTXT(strings.Join(r.Txt, "")), len(c.txt) > 0 || len(c.txtRegex) > 0
+	}
+
+	return false, false
+}
+
+func (c *answerChecker) matchesTXT(s string) bool {
+	if c.txt[s] {
+		return true
+	}
+
+	for _, re := range c.txtRegex {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalizeName(name string) string {
+	return mdns.Fqdn(strings.ToLower(name))
+}
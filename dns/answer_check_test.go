@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package dns
+
+import (
+	"testing"
+
+	"github.com/czerwonk/atlas_exporter/config"
+	mdns "github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnswerCheck(t *testing.T) {
+	checker := newAnswerChecker(config.ExpectedAnswers{
+		Addresses: []string{"192.0.2.1", "2001:db8:0::1"},
+		CNAMEs:    []string{"CDN.example.com"},
+		TXTRegex:  []string{"^v=spf1 "},
+	})
+
+	tests := []struct {
+		name               string
+		records            []string
+		expectedChecked    int
+		expectedUnexpected int
+	}{
+		{
+			name:            "expected records",
+			records:         []string{"www.example.com. 300 IN CNAME cdn.example.com.", "cdn.example.com. 60 IN A 192.0.2.1", "cdn.example.com. 60 IN AAAA 2001:db8::1"},
+			expectedChecked: 3,
+		},
+		{
+			name:               "hijacked address",
+			records:            []string{"www.example.com. 300 IN A 198.51.100.7"},
+			expectedChecked:    1,
+			expectedUnexpected: 1,
+		},
+		{
+			name:            "TXT regex",
+			records:         []string{`example.com. 300 IN TXT "v=spf1 " "-all"`},
+			expectedChecked: 1,
+		},
+		{
+			name:    "unchecked record type",
+			records: []string{"example.com. 300 IN MX 10 mail.example.com."},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(te *testing.T) {
+			msg := new(mdns.Msg)
+			for _, r := range test.records {
+				rr, err := mdns.NewRR(r)
+				if err != nil {
+					te.Fatal(err)
+				}
+
+				msg.Answer = append(msg.Answer, rr)
+			}
+
+			checked, unexpected := checker.check(msg)
+			assert.Equal(te, test.expectedChecked, checked)
+			assert.Equal(te, test.expectedUnexpected, unexpected)
+		})
+	}
+
+	assert.Nil(t, newAnswerChecker(config.ExpectedAnswers{}))
+}
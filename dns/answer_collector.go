@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package dns
+
+import (
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/czerwonk/atlas_exporter/exporter"
+	"github.com/czerwonk/atlas_exporter/probe"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type unexpectedAnswersCollector struct {
+	checker    *answerChecker
+	responses  *responseCache
+	unexpected prometheus.Counter
+}
+
+func newUnexpectedAnswersCollector(id, ipVersion string, checker *answerChecker, cache *responseCache) exporter.ResultCollector {
+	return &unexpectedAnswersCollector{
+		checker:   checker,
+		responses: cache,
+		unexpected: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: ns,
+			Subsystem: sub,
+			Name:      "unexpected_answers_total",
+			Help:      "Number of records in the answer section not matching the expected answers",
+			ConstLabels: prometheus.Labels{
+				"measurement": id,
+				"ip_version":  ipVersion,
+			},
+		}),
+	}
+}
+
+func (c *unexpectedAnswersCollector) ProcessResult(r *measurement.Result, p *probe.Probe) {
+	for _, resp := range c.responses.responses(r) {
+		msg := resp.msg
+		if msg == nil {
+			continue
+		}
+
+		_, unexpected := c.checker.check(msg)
+		c.unexpected.Add(float64(unexpected))
+	}
+}
+
+func (c *unexpectedAnswersCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.unexpected.Describe(ch)
+}
+
+func (c *unexpectedAnswersCollector) Collect(ch chan<- prometheus.Metric) {
+	c.unexpected.Collect(ch)
+}
@@ -5,6 +5,7 @@ package dns
 import (
 	"github.com/czerwonk/atlas_exporter/config"
 	"github.com/czerwonk/atlas_exporter/exporter"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 const (
@@ -12,15 +13,39 @@ const (
 	sub = "dns"
 )
 
+func init() {
+	exporter.Register("dns", NewMeasurement)
+}
+
 // NewMeasurement returns a new instance of `exorter.Measurement` for a DNS measurement
 func NewMeasurement(id, ipVersion string, cfg *config.Config) *exporter.Measurement {
+	m, _ := cfg.MeasurementByID(id)
+	checker := newAnswerChecker(m.DNS.ExpectedAnswers)
+
+	cache := newResponseCache()
+	buckets := cfg.HistogramBucketsForMeasurement(id)
+	partition := exporter.NewHistogramPartition(cfg.HistogramPartition)
 	opts := []exporter.MeasurementOpt{
-		exporter.WithHistograms(newRttHistogram(id, ipVersion, cfg.HistogramBuckets.DNS.Rtt)),
+		exporter.WithHistograms(newRttHistogram(id, ipVersion, buckets.DNS.Rtt, buckets.DNS.Native, partition)),
+		exporter.WithCollectors(
+			newSOACollector(id, ipVersion, cache),
+			newServerIDCollector(id, ipVersion, cache),
+			newErrorCollector(id, ipVersion, cache),
+		),
+	}
+
+	if checker != nil {
+		opts = append(opts, exporter.WithCollectors(newUnexpectedAnswersCollector(id, ipVersion, checker, cache)))
+	}
+
+	if m.Summary.Enabled() {
+		opts = append(opts, exporter.WithHistograms(exporter.NewRttSummary(prometheus.BuildFQName(ns, sub, "rtt_summary"),
+			"Summary of round trip times over all DNS requests within a sliding window", id, ipVersion, m.Summary, partition, rtts)))
 	}
 
 	if cfg.FilterInvalidResults {
 		opts = append(opts, exporter.WithValidator(&exporter.DefaultResultValidator{}))
 	}
 
-	return exporter.NewMeasurement(&dnsExporter{id}, opts...)
+	return exporter.NewMeasurement(&dnsExporter{id: id, checker: checker, responses: cache}, opts...)
 }
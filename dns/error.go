@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package dns
+
+import mdns "github.com/miekg/dns"
+
+// Error classes of DNS responses. The error object of a result only exposes the fields `timeout` and `getaddrinfo`
+// in the ripeatlas library, so the classes are mapped as follows:
+//
+//	timeout:    `timeout` is set (no response within the timeout)
+//	nameserver: `getaddrinfo` is set (the address of the name server could not be resolved)
+//	other:      any other error reported by the probe (e.g. `socket`, not exposed by the library)
+//	rcode:      a response was received with a response code other than NOERROR
+const (
+	errorTimeout    = "timeout"
+	errorNameserver = "nameserver"
+	errorOther      = "other"
+	errorRcode      = "rcode"
+)
+
+var errorClasses = []string{
+	errorTimeout,
+	errorNameserver,
+	errorOther,
+	errorRcode,
+}
+
+// errorClass classifies the error of a response (empty if the query was successful)
+func errorClass(resp *response) string {
+	if resp.err != nil {
+		switch {
+		case resp.err.Timeout() > 0:
+			return errorTimeout
+		case len(resp.err.Getaddrinfo()) > 0:
+			return errorNameserver
+		default:
+			return errorOther
+		}
+	}
+
+	if resp.msg != nil && resp.msg.Rcode != mdns.RcodeSuccess {
+		return errorRcode
+	}
+
+	return ""
+}
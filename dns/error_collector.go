@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package dns
+
+import (
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/czerwonk/atlas_exporter/exporter"
+	"github.com/czerwonk/atlas_exporter/probe"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type errorCollector struct {
+	responses *responseCache
+	errors    *prometheus.CounterVec
+}
+
+func newErrorCollector(id, ipVersion string, cache *responseCache) exporter.ResultCollector {
+	return &errorCollector{
+		responses: cache,
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Subsystem: sub,
+			Name:      "errors_total",
+			Help:      "Number of failed queries by error class (timeout, nameserver, other, rcode)",
+			ConstLabels: prometheus.Labels{
+				"measurement": id,
+				"ip_version":  ipVersion,
+			},
+		}, []string{"error"}),
+	}
+}
+
+func (c *errorCollector) ProcessResult(r *measurement.Result, p *probe.Probe) {
+	for _, resp := range c.responses.responses(r) {
+		if e := errorClass(resp); len(e) > 0 {
+			c.errors.WithLabelValues(e).Inc()
+		}
+	}
+}
+
+func (c *errorCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.errors.Describe(ch)
+}
+
+func (c *errorCollector) Collect(ch chan<- prometheus.Metric) {
+	c.errors.Collect(ch)
+}
@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package dns
+
+import (
+	"testing"
+
+	mdns "github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorClass(t *testing.T) {
+	nxdomain := new(mdns.Msg)
+	nxdomain.SetQuestion("example.com.", mdns.TypeA)
+	nxdomain.Response = true
+	nxdomain.Rcode = mdns.RcodeNameError
+
+	noerror := new(mdns.Msg)
+	noerror.SetQuestion("example.com.", mdns.TypeA)
+	noerror.Response = true
+
+	tests := []struct {
+		name     string
+		result   string
+		expected string
+	}{
+		{
+			name:     "timeout",
+			result:   `{"type": "dns", "error": {"timeout": 5000}}`,
+			expected: errorTimeout,
+		},
+		{
+			name:     "getaddrinfo",
+			result:   `{"type": "dns", "error": {"getaddrinfo": "Name or service not known"}}`,
+			expected: errorNameserver,
+		},
+		{
+			name:     "socket",
+			result:   `{"type": "dns", "error": {"socket": "connect failed Network is unreachable"}}`,
+			expected: errorOther,
+		},
+		{
+			name:     "rcode",
+			result:   `{"type": "dns", "result": {"rt": 10, "abuf": "` + abuf(t, nxdomain) + `"}}`,
+			expected: errorRcode,
+		},
+		{
+			name:     "success",
+			result:   `{"type": "dns", "result": {"rt": 10, "abuf": "` + abuf(t, noerror) + `"}}`,
+			expected: "",
+		},
+		{
+			name:     "success without answer buffer",
+			result:   `{"type": "dns", "result": {"rt": 10}}`,
+			expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(te *testing.T) {
+			r := parseResult(te, test.result)
+			assert.Equal(te, test.expected, errorClass(responses(r)[0]))
+		})
+	}
+}
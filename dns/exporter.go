@@ -7,13 +7,25 @@ import (
 
 	"github.com/DNS-OARC/ripeatlas/measurement"
 	"github.com/czerwonk/atlas_exporter/probe"
+	mdns "github.com/miekg/dns"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 var (
-	labels      []string
-	successDesc *prometheus.Desc
-	rttDesc     *prometheus.Desc
+	labels          []string
+	successDesc     *prometheus.Desc
+	rttDesc         *prometheus.Desc
+	rcodeDesc       *prometheus.Desc
+	answersDesc     *prometheus.Desc
+	authoritiesDesc *prometheus.Desc
+	additionalsDesc *prometheus.Desc
+	sizeDesc        *prometheus.Desc
+	flagDesc        *prometheus.Desc
+	minTTLDesc      *prometheus.Desc
+	soaSerialDesc   *prometheus.Desc
+	serverIDDesc    *prometheus.Desc
+	answerMatchDesc *prometheus.Desc
+	errorDesc       *prometheus.Desc
 )
 
 func init() {
@@ -21,35 +33,105 @@ func init() {
 
 	successDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "success"), "Destination was reachable", labels, nil)
 	rttDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "rtt"), "Roundtrip time in ms", labels, nil)
+	errorDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "error"), "Query failed with an error of this class (timeout, nameserver, other, rcode)", append(labels, "error"), nil)
+	rcodeDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "rcode"), "Response code (0 = NOERROR)", labels, nil)
+	answersDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "answers"), "Number of records in the answer section", labels, nil)
+	authoritiesDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "authorities"), "Number of records in the authority section", labels, nil)
+	additionalsDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "additionals"), "Number of records in the additional section", labels, nil)
+	sizeDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "response_size"), "Size of the response in bytes", labels, nil)
+	flagDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "flag"), "Flag is set in the response header (aa, tc, ad)", append(labels, "flag"), nil)
+	minTTLDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "answer_min_ttl"), "Lowest TTL of all records in the answer section", labels, nil)
+	soaSerialDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "soa_serial"), "Serial of the SOA record returned by the server", labels, nil)
+	answerMatchDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "answer_match"), "All checked records in the answer section match the expected answers", labels, nil)
+	serverIDDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "server_id_info"), "Instance of the server answering the query (NSID, hostname.bind or id.server)", append(labels, "server_id"), nil)
 }
 
 type dnsExporter struct {
-	id string
+	id        string
+	checker   *answerChecker
+	responses *responseCache
 }
 
 // Export exports a prometheus metric
 func (m *dnsExporter) Export(res *measurement.Result, probe *probe.Probe, ch chan<- prometheus.Metric) {
-	labelValues := []string{
-		m.id,
-		strconv.Itoa(probe.ID),
-		res.DstAddr(),
-		strconv.Itoa(probe.ASNForIPVersion(res.Af())),
-		strconv.Itoa(res.Af()),
-		probe.CountryCode,
-		probe.Latitude(),
-		probe.Longitude(),
+	for _, resp := range m.responses.responses(res) {
+		labelValues := []string{
+			m.id,
+			strconv.Itoa(probe.ID),
+			resp.dstAddr,
+			strconv.Itoa(probe.ASNForIPVersion(resp.af)),
+			strconv.Itoa(resp.af),
+			probe.CountryCode,
+			probe.Latitude(),
+			probe.Longitude(),
+		}
+
+		var rtt float64
+		if resp.result != nil {
+			rtt = resp.result.Rt()
+		}
+
+		if rtt > 0 {
+			ch <- prometheus.MustNewConstMetric(successDesc, prometheus.GaugeValue, 1, labelValues...)
+			ch <- prometheus.MustNewConstMetric(rttDesc, prometheus.GaugeValue, rtt, labelValues...)
+		} else {
+			ch <- prometheus.MustNewConstMetric(successDesc, prometheus.GaugeValue, 0, labelValues...)
+		}
+
+		errClass := errorClass(resp)
+		for _, e := range errorClasses {
+			ch <- prometheus.MustNewConstMetric(errorDesc, prometheus.GaugeValue, boolToFloat(e == errClass), append(labelValues, e)...)
+		}
+
+		if resp.result != nil {
+			m.exportResponse(resp, labelValues, ch)
+		}
+	}
+}
+
+func (m *dnsExporter) exportResponse(resp *response, labelValues []string, ch chan<- prometheus.Metric) {
+	if resp.result.Size() > 0 {
+		ch <- prometheus.MustNewConstMetric(sizeDesc, prometheus.GaugeValue, float64(resp.result.Size()), labelValues...)
 	}
 
-	var rtt float64
-	if res.DnsResult() != nil {
-		rtt = res.DnsResult().Rt()
+	if serial, found := soaSerial(resp); found {
+		ch <- prometheus.MustNewConstMetric(soaSerialDesc, prometheus.GaugeValue, float64(serial), labelValues...)
 	}
 
-	if rtt > 0 {
-		ch <- prometheus.MustNewConstMetric(successDesc, prometheus.GaugeValue, 1, labelValues...)
-		ch <- prometheus.MustNewConstMetric(rttDesc, prometheus.GaugeValue, rtt, labelValues...)
-	} else {
-		ch <- prometheus.MustNewConstMetric(successDesc, prometheus.GaugeValue, 0, labelValues...)
+	msg := resp.msg
+	if msg == nil {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(rcodeDesc, prometheus.GaugeValue, float64(msg.Rcode), labelValues...)
+	ch <- prometheus.MustNewConstMetric(answersDesc, prometheus.GaugeValue, float64(len(msg.Answer)), labelValues...)
+	ch <- prometheus.MustNewConstMetric(authoritiesDesc, prometheus.GaugeValue, float64(len(msg.Ns)), labelValues...)
+	ch <- prometheus.MustNewConstMetric(additionalsDesc, prometheus.GaugeValue, float64(len(msg.Extra)), labelValues...)
+	m.exportFlags(msg, labelValues, ch)
+
+	if ttl, found := minAnswerTTL(msg); found {
+		ch <- prometheus.MustNewConstMetric(minTTLDesc, prometheus.GaugeValue, float64(ttl), labelValues...)
+	}
+
+	if m.checker != nil {
+		checked, unexpected := m.checker.check(msg)
+		ch <- prometheus.MustNewConstMetric(answerMatchDesc, prometheus.GaugeValue, boolToFloat(checked > 0 && unexpected == 0), labelValues...)
+	}
+
+	if id := serverID(msg); len(id) > 0 {
+		ch <- prometheus.MustNewConstMetric(serverIDDesc, prometheus.GaugeValue, 1, append(labelValues, id)...)
+	}
+}
+
+func (m *dnsExporter) exportFlags(msg *mdns.Msg, labelValues []string, ch chan<- prometheus.Metric) {
+	flags := map[string]bool{
+		"aa": msg.Authoritative,
+		"tc": msg.Truncated,
+		"ad": msg.AuthenticatedData,
+	}
+
+	for f, set := range flags {
+		ch <- prometheus.MustNewConstMetric(flagDesc, prometheus.GaugeValue, boolToFloat(set), append(labelValues, f)...)
 	}
 }
 
@@ -57,4 +139,26 @@ func (m *dnsExporter) Export(res *measurement.Result, probe *probe.Probe, ch cha
 func (m *dnsExporter) Describe(ch chan<- *prometheus.Desc) {
 	ch <- successDesc
 	ch <- rttDesc
+	ch <- errorDesc
+	ch <- rcodeDesc
+	ch <- answersDesc
+	ch <- authoritiesDesc
+	ch <- additionalsDesc
+	ch <- sizeDesc
+	ch <- flagDesc
+	ch <- minTTLDesc
+	ch <- soaSerialDesc
+	ch <- serverIDDesc
+
+	if m.checker != nil {
+		ch <- answerMatchDesc
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
 }
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package dns
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/DNS-OARC/ripeatlas/measurement/dns"
+	mdns "github.com/miekg/dns"
+	log "github.com/sirupsen/logrus"
+)
+
+// response is a single response of a DNS measurement result. When the probe's local resolvers are used there is one
+// response for each resolver.
+type response struct {
+	dstAddr string
+	af      int
+	result  *dns.Result
+	err     *dns.Error
+	// msg is the decoded answer buffer (nil if not present or invalid)
+	msg *mdns.Msg
+}
+
+// responses returns all responses of a result (entries of the result set or the result itself)
+func responses(r *measurement.Result) []*response {
+	if len(r.DnsResultsets()) == 0 {
+		return []*response{
+			newResponse(r.DstAddr(), r.Af(), r.DnsResult(), r.DnsError()),
+		}
+	}
+
+	res := make([]*response, 0, len(r.DnsResultsets()))
+	idx := make(map[string]int)
+	for _, s := range r.DnsResultsets() {
+		af := s.Af()
+		if af == 0 {
+			af = r.Af()
+		}
+
+		resp := newResponse(s.DstAddr(), af, s.Result(), s.DnsError())
+
+		// only the last response for each resolver is used to keep label sets unique
+		key := resp.dstAddr + "/" + strconv.Itoa(af)
+		if i, found := idx[key]; found {
+			res[i] = resp
+			continue
+		}
+
+		idx[key] = len(res)
+		res = append(res, resp)
+	}
+
+	return res
+}
+
+// responseCache holds the decoded responses of the latest result of each probe, so the answer buffers of a result
+// are decoded once and shared by the exporter, histograms and collectors of a measurement
+type responseCache struct {
+	results map[int]*cachedResponses
+	mu      sync.Mutex
+}
+
+type cachedResponses struct {
+	result    *measurement.Result
+	responses []*response
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{results: make(map[int]*cachedResponses)}
+}
+
+// responses returns the responses of a result (decoded only if the result is not the one cached for the probe)
+func (c *responseCache) responses(r *measurement.Result) []*response {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, found := c.results[r.PrbId()]; found && cached.result == r {
+		return cached.responses
+	}
+
+	res := responses(r)
+	c.results[r.PrbId()] = &cachedResponses{result: r, responses: res}
+
+	return res
+}
+
+func newResponse(dstAddr string, af int, result *dns.Result, err *dns.Error) *response {
+	resp := &response{dstAddr: dstAddr, af: af, result: result, err: err}
+	if result != nil {
+		resp.msg = unpackResponse(result)
+	}
+
+	return resp
+}
+
+// unpackResponse decodes the answer buffer of a result (nil if not present or invalid)
+func unpackResponse(r *dns.Result) *mdns.Msg {
+	msg, err := r.UnpackAbuf()
+	if err != nil {
+		log.Debugf("could not decode answer buffer: %v", err)
+		return nil
+	}
+
+	return msg
+}
+
+// minAnswerTTL returns the lowest TTL of all records in the answer section
+func minAnswerTTL(msg *mdns.Msg) (ttl uint32, found bool) {
+	for _, rr := range msg.Answer {
+		h := rr.Header()
+		if !found || h.Ttl < ttl {
+			ttl = h.Ttl
+			found = true
+		}
+	}
+
+	return ttl, found
+}
@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package dns
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	mdns "github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+)
+
+func parseResult(t *testing.T, s string) *measurement.Result {
+	var r measurement.Result
+	err := json.Unmarshal([]byte(s), &r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &r
+}
+
+func abuf(t *testing.T, msg *mdns.Msg) string {
+	b, err := msg.Pack()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func TestUnpackResponse(t *testing.T) {
+	msg := new(mdns.Msg)
+	msg.SetQuestion("example.com.", mdns.TypeA)
+	msg.Response = true
+	msg.Rcode = mdns.RcodeNameError
+
+	tests := []struct {
+		name      string
+		result    string
+		wantsMsg  bool
+		wantRcode int
+	}{
+		{
+			name:      "valid answer buffer",
+			result:    `{"type": "dns", "result": {"rt": 10.5, "abuf": "` + abuf(t, msg) + `"}}`,
+			wantsMsg:  true,
+			wantRcode: mdns.RcodeNameError,
+		},
+		{
+			name:   "no answer buffer",
+			result: `{"type": "dns", "result": {"rt": 10.5}}`,
+		},
+		{
+			name:   "invalid base64",
+			result: `{"type": "dns", "result": {"rt": 10.5, "abuf": "%%%"}}`,
+		},
+		{
+			name:   "truncated message",
+			result: `{"type": "dns", "result": {"rt": 10.5, "abuf": "` + base64.StdEncoding.EncodeToString([]byte{0x12, 0x34, 0x81}) + `"}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(te *testing.T) {
+			r := parseResult(te, test.result)
+
+			m := unpackResponse(r.DnsResult())
+			if !test.wantsMsg {
+				assert.Nil(te, m)
+				return
+			}
+
+			if assert.NotNil(te, m) {
+				assert.Equal(te, test.wantRcode, m.Rcode)
+				assert.Equal(te, "example.com.", m.Question[0].Name)
+			}
+		})
+	}
+}
+
+type expectedResponse struct {
+	dstAddr string
+	af      int
+	rt      float64
+	err     bool
+}
+
+func TestResponses(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   string
+		expected []expectedResponse
+	}{
+		{
+			name:   "single result",
+			result: `{"type": "dns", "af": 6, "dst_addr": "2001:db8::53", "result": {"rt": 12.5}}`,
+			expected: []expectedResponse{
+				{dstAddr: "2001:db8::53", af: 6, rt: 12.5},
+			},
+		},
+		{
+			name:   "single error",
+			result: `{"type": "dns", "af": 4, "dst_addr": "192.0.2.53", "error": {"timeout": 5000}}`,
+			expected: []expectedResponse{
+				{dstAddr: "192.0.2.53", af: 4, err: true},
+			},
+		},
+		{
+			name: "result set with retries of the same resolver",
+			result: `{"type": "dns", "af": 4, "resultset": [
+  {"af": 4, "dst_addr": "192.0.2.53", "result": {"rt": 30.1}},
+  {"dst_addr": "192.0.2.53", "result": {"rt": 10.2}},
+  {"af": 6, "dst_addr": "2001:db8::53", "error": {"timeout": 5000}},
+  {"af": 4, "dst_addr": "198.51.100.53", "result": {"rt": 5.5}}
+]}`,
+			expected: []expectedResponse{
+				{dstAddr: "192.0.2.53", af: 4, rt: 10.2},
+				{dstAddr: "2001:db8::53", af: 6, err: true},
+				{dstAddr: "198.51.100.53", af: 4, rt: 5.5},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(te *testing.T) {
+			res := responses(parseResult(te, test.result))
+
+			if !assert.Len(te, res, len(test.expected)) {
+				return
+			}
+
+			for i, e := range test.expected {
+				assert.Equal(te, e.dstAddr, res[i].dstAddr)
+				assert.Equal(te, e.af, res[i].af)
+				assert.Equal(te, e.err, res[i].err != nil)
+
+				if e.err {
+					continue
+				}
+
+				if assert.NotNil(te, res[i].result) {
+					assert.Equal(te, e.rt, res[i].result.Rt())
+				}
+			}
+		})
+	}
+}
+
+func TestMinAnswerTTL(t *testing.T) {
+	msg := new(mdns.Msg)
+	msg.SetQuestion("example.com.", mdns.TypeA)
+
+	_, found := minAnswerTTL(msg)
+	assert.False(t, found)
+
+	msg.Answer = []mdns.RR{
+		&mdns.CNAME{Hdr: mdns.RR_Header{Name: "example.com.", Rrtype: mdns.TypeCNAME, Class: mdns.ClassINET, Ttl: 3600}, Target: "cdn.example.net."},
+		&mdns.A{Hdr: mdns.RR_Header{Name: "cdn.example.net.", Rrtype: mdns.TypeA, Class: mdns.ClassINET, Ttl: 60}},
+		&mdns.A{Hdr: mdns.RR_Header{Name: "cdn.example.net.", Rrtype: mdns.TypeA, Class: mdns.ClassINET, Ttl: 300}},
+	}
+	msg.Ns = []mdns.RR{
+		&mdns.NS{Hdr: mdns.RR_Header{Name: "example.net.", Rrtype: mdns.TypeNS, Class: mdns.ClassINET, Ttl: 5}, Ns: "ns.example.net."},
+	}
+
+	ttl, found := minAnswerTTL(msg)
+	assert.True(t, found)
+	assert.Equal(t, uint32(60), ttl)
+
+	msg.Answer[0].Header().Ttl = 0
+	ttl, _ = minAnswerTTL(msg)
+	assert.Equal(t, uint32(0), ttl)
+}
+
+func TestResponseCache(t *testing.T) {
+	msg := new(mdns.Msg)
+	msg.SetQuestion("example.com.", mdns.TypeA)
+	s := `{"type": "dns", "af": 4, "prb_id": 1, "dst_addr": "192.0.2.53", "result": {"rt": 12.5, "abuf": "` + abuf(t, msg) + `"}}`
+
+	c := newResponseCache()
+	r := parseResult(t, s)
+
+	first := c.responses(r)
+	if assert.Len(t, first, 1) {
+		assert.NotNil(t, first[0].msg)
+	}
+	assert.Same(t, first[0], c.responses(r)[0], "responses of the same result are decoded once")
+
+	next := parseResult(t, s)
+	assert.NotSame(t, first[0], c.responses(next)[0], "newer result of the probe is decoded")
+	assert.Same(t, c.responses(next)[0], c.responses(next)[0])
+}
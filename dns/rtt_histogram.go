@@ -4,21 +4,25 @@ package dns
 
 import (
 	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/czerwonk/atlas_exporter/config"
 	"github.com/czerwonk/atlas_exporter/exporter"
+	"github.com/czerwonk/atlas_exporter/probe"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 type rttHistogram struct {
-	rtt prometheus.Histogram
+	rtt       *prometheus.HistogramVec
+	partition *exporter.HistogramPartition
 }
 
-func newRttHistogram(id, ipVersion string, buckets []float64) exporter.Histogram {
+func newRttHistogram(id, ipVersion string, buckets []float64, native config.NativeHistogram, partition *exporter.HistogramPartition) exporter.Histogram {
 	if buckets == nil {
 		buckets = []float64{10, 20, 50, 100}
 	}
 
 	return &rttHistogram{
-		rtt: prometheus.NewHistogram(prometheus.HistogramOpts{
+		partition: partition,
+		rtt: partition.NewHistogramVec(exporter.WithNativeHistogram(prometheus.HistogramOpts{
 			Namespace: ns,
 			Subsystem: sub,
 			Name:      "rtt_hist",
@@ -28,20 +32,42 @@ func newRttHistogram(id, ipVersion string, buckets []float64) exporter.Histogram
 				"measurement": id,
 				"ip_version":  ipVersion,
 			},
-		}),
+		}, native)),
 	}
 }
 
-func (h *rttHistogram) ProcessResult(r *measurement.Result) {
-	if r.DnsResult() == nil {
-		return
-	}
-
-	if r.DnsResult().Rt() > 0 {
-		h.rtt.Observe(r.DnsResult().Rt())
+func (h *rttHistogram) ProcessResult(r *measurement.Result, probe *probe.Probe) {
+	for _, rtt := range rtts(r) {
+		h.rtt.WithLabelValues(h.partition.LabelValues(probe, rtt.Af)...).Observe(rtt.Value)
 	}
 }
 
-func (h *rttHistogram) Hist() prometheus.Histogram {
+func (h *rttHistogram) Hist() prometheus.Collector {
 	return h.rtt
 }
+
+// rtts returns the round trip times of all entries of a result with the address family of each entry. In contrast
+// to `responses` all entries of the result set are used, including several entries for the same resolver.
+func rtts(r *measurement.Result) []exporter.Value {
+	res := make([]exporter.Value, 0)
+	if len(r.DnsResultsets()) == 0 {
+		if r.DnsResult() != nil && r.DnsResult().Rt() > 0 {
+			res = append(res, exporter.Value{Value: r.DnsResult().Rt(), Af: r.Af()})
+		}
+
+		return res
+	}
+
+	for _, s := range r.DnsResultsets() {
+		af := s.Af()
+		if af == 0 {
+			af = r.Af()
+		}
+
+		if s.Result() != nil && s.Result().Rt() > 0 {
+			res = append(res, exporter.Value{Value: s.Result().Rt(), Af: af})
+		}
+	}
+
+	return res
+}
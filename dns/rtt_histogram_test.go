@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package dns
+
+import (
+	"testing"
+
+	"github.com/czerwonk/atlas_exporter/exporter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRttsUseAfOfResponse(t *testing.T) {
+	r := parseResult(t, `{"type": "dns", "af": 4, "resultset": [
+  {"af": 4, "dst_addr": "192.0.2.53", "result": {"rt": 30.1}},
+  {"af": 6, "dst_addr": "2001:db8::53", "result": {"rt": 12.5}},
+  {"af": 6, "dst_addr": "2001:db8::54", "error": {"timeout": 5000}}
+]}`)
+
+	assert.Equal(t, []exporter.Value{{Value: 30.1, Af: 4}, {Value: 12.5, Af: 6}}, rtts(r))
+}
+
+func TestRttsUseAllEntries(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   string
+		expected []exporter.Value
+	}{
+		{
+			name:     "single result",
+			result:   `{"type": "dns", "af": 6, "dst_addr": "2001:db8::53", "result": {"rt": 12.5}}`,
+			expected: []exporter.Value{{Value: 12.5, Af: 6}},
+		},
+		{
+			name:     "error",
+			result:   `{"type": "dns", "af": 4, "dst_addr": "192.0.2.53", "error": {"timeout": 5000}}`,
+			expected: []exporter.Value{},
+		},
+		{
+			name: "result set with retries of the same resolver",
+			result: `{"type": "dns", "af": 4, "resultset": [
+  {"af": 4, "dst_addr": "192.0.2.53", "result": {"rt": 30.1}},
+  {"dst_addr": "192.0.2.53", "result": {"rt": 10.2}},
+  {"af": 4, "dst_addr": "198.51.100.53", "result": {"rt": 5.5}}
+]}`,
+			expected: []exporter.Value{{Value: 30.1, Af: 4}, {Value: 10.2, Af: 4}, {Value: 5.5, Af: 4}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(te *testing.T) {
+			assert.Equal(te, test.expected, rtts(parseResult(te, test.result)))
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package dns
+
+import (
+	"encoding/hex"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	mdns "github.com/miekg/dns"
+)
+
+// serverID identifies the instance of an (anycast) server answering a query. The NSID option (RFC 5001) is preferred,
+// otherwise the TXT answer of a CHAOS query for hostname.bind or id.server (RFC 4892) is used.
+func serverID(msg *mdns.Msg) string {
+	if id := nsid(msg); len(id) > 0 {
+		return id
+	}
+
+	return chaosID(msg)
+}
+
+func nsid(msg *mdns.Msg) string {
+	opt := msg.IsEdns0()
+	if opt == nil {
+		return ""
+	}
+
+	for _, o := range opt.Option {
+		n, ok := o.(*mdns.EDNS0_NSID)
+		if !ok || len(n.Nsid) == 0 {
+			continue
+		}
+
+		b, err := hex.DecodeString(n.Nsid)
+		if err != nil || !isPrintable(string(b)) {
+			return n.Nsid
+		}
+
+		return string(b)
+	}
+
+	return ""
+}
+
+func chaosID(msg *mdns.Msg) string {
+	if len(msg.Question) == 0 || msg.Question[0].Qclass != mdns.ClassCHAOS {
+		return ""
+	}
+
+	switch strings.ToLower(msg.Question[0].Name) {
+	case "hostname.bind.", "id.server.":
+	default:
+		return ""
+	}
+
+	for _, rr := range msg.Answer {
+		if txt, ok := rr.(*mdns.TXT); ok {
+			return strings.Join(txt.Txt, " ")
+		}
+	}
+
+	return ""
+}
+
+// isPrintable returns true if the string is valid UTF-8 without control characters (invalid UTF-8 would decode to
+// the printable replacement character and can not be used as label value)
+func isPrintable(s string) bool {
+	if !utf8.ValidString(s) {
+		return false
+	}
+
+	for _, r := range s {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package dns
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/czerwonk/atlas_exporter/exporter"
+	"github.com/czerwonk/atlas_exporter/probe"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type serverIDCollector struct {
+	responses  *responseCache
+	ids        map[int][]string
+	probesDesc *prometheus.Desc
+	mu         sync.Mutex
+}
+
+func newServerIDCollector(id, ipVersion string, cache *responseCache) exporter.ResultCollector {
+	constLabels := prometheus.Labels{
+		"measurement": id,
+		"ip_version":  ipVersion,
+	}
+
+	return &serverIDCollector{
+		responses:  cache,
+		ids:        make(map[int][]string),
+		probesDesc: prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "server_id_probes"), "Number of probes answered by a server instance (NSID, hostname.bind or id.server)", []string{"server_id"}, constLabels),
+	}
+}
+
+func (c *serverIDCollector) ProcessResult(r *measurement.Result, p *probe.Probe) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ids := make([]string, 0)
+	for _, resp := range c.responses.responses(r) {
+		msg := resp.msg
+		if msg == nil {
+			continue
+		}
+
+		if id := serverID(msg); len(id) > 0 && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		delete(c.ids, r.PrbId())
+		return
+	}
+
+	c.ids[r.PrbId()] = ids
+}
+
+func (c *serverIDCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.probesDesc
+}
+
+func (c *serverIDCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counts := make(map[string]int)
+	for _, ids := range c.ids {
+		for _, id := range ids {
+			counts[id]++
+		}
+	}
+
+	for id, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.probesDesc, prometheus.GaugeValue, float64(count), id)
+	}
+}
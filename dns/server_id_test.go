@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package dns
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	mdns "github.com/miekg/dns"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerID(t *testing.T) {
+	nsidMsg := new(mdns.Msg)
+	nsidMsg.SetQuestion("example.com.", mdns.TypeA)
+	opt := nsidMsg.SetEdns0(1232, false).IsEdns0()
+	opt.Option = append(opt.Option, &mdns.EDNS0_NSID{Code: mdns.EDNS0NSID, Nsid: hex.EncodeToString([]byte("fra1.ns.example"))})
+
+	binaryNSIDMsg := new(mdns.Msg)
+	binaryNSIDMsg.SetQuestion("example.com.", mdns.TypeA)
+	opt = binaryNSIDMsg.SetEdns0(1232, false).IsEdns0()
+	opt.Option = append(opt.Option, &mdns.EDNS0_NSID{Code: mdns.EDNS0NSID, Nsid: "00ff"})
+
+	invalidUTF8NSIDMsg := new(mdns.Msg)
+	invalidUTF8NSIDMsg.SetQuestion("example.com.", mdns.TypeA)
+	opt = invalidUTF8NSIDMsg.SetEdns0(1232, false).IsEdns0()
+	opt.Option = append(opt.Option, &mdns.EDNS0_NSID{Code: mdns.EDNS0NSID, Nsid: "ff6162"})
+
+	chaosMsg := new(mdns.Msg)
+	chaosMsg.Question = []mdns.Question{{Name: "HOSTNAME.BIND.", Qtype: mdns.TypeTXT, Qclass: mdns.ClassCHAOS}}
+	chaosMsg.Answer = []mdns.RR{&mdns.TXT{
+		Hdr: mdns.RR_Header{Name: "hostname.bind.", Rrtype: mdns.TypeTXT, Class: mdns.ClassCHAOS},
+		Txt: []string{"ams2"},
+	}}
+
+	inetMsg := new(mdns.Msg)
+	inetMsg.SetQuestion("hostname.bind.", mdns.TypeTXT)
+	inetMsg.Answer = []mdns.RR{&mdns.TXT{
+		Hdr: mdns.RR_Header{Name: "hostname.bind.", Rrtype: mdns.TypeTXT, Class: mdns.ClassINET},
+		Txt: []string{"not an instance"},
+	}}
+
+	assert.Equal(t, "fra1.ns.example", serverID(nsidMsg))
+	assert.Equal(t, "00ff", serverID(binaryNSIDMsg))
+	assert.Equal(t, "ff6162", serverID(invalidUTF8NSIDMsg))
+	assert.Equal(t, "ams2", serverID(chaosMsg))
+	assert.Equal(t, "", serverID(inetMsg))
+}
+
+func TestServerIDCollectorInvalidUTF8(t *testing.T) {
+	msg := new(mdns.Msg)
+	msg.SetQuestion("example.com.", mdns.TypeA)
+	opt := msg.SetEdns0(1232, false).IsEdns0()
+	opt.Option = append(opt.Option, &mdns.EDNS0_NSID{Code: mdns.EDNS0NSID, Nsid: "ff6162"})
+
+	r := parseResult(t, `{"type": "dns", "af": 4, "prb_id": 1, "dst_addr": "192.0.2.53", "result": {"rt": 12.5, "abuf": "`+abuf(t, msg)+`"}}`)
+
+	c := newServerIDCollector("1", "4", newResponseCache())
+	c.ProcessResult(r, nil)
+
+	expected := `
+# HELP atlas_dns_server_id_probes Number of probes answered by a server instance (NSID, hostname.bind or id.server)
+# TYPE atlas_dns_server_id_probes gauge
+atlas_dns_server_id_probes{ip_version="4",measurement="1",server_id="ff6162"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+}
@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package dns
+
+import (
+	mdns "github.com/miekg/dns"
+)
+
+// soaSerial returns the serial of the first SOA record in the answer section of a response
+func soaSerial(resp *response) (uint32, bool) {
+	if resp.result == nil {
+		return 0, false
+	}
+
+	if resp.msg != nil {
+		for _, rr := range resp.msg.Answer {
+			if soa, ok := rr.(*mdns.SOA); ok {
+				return soa.Serial, true
+			}
+		}
+	}
+
+	// fallback to the records decoded by the probe
+	for _, a := range resp.result.Answers() {
+		if a.Type() == "SOA" {
+			return uint32(a.Serial()), true
+		}
+	}
+
+	return 0, false
+}
+
+// serialAfter compares two serials using serial number arithmetic (RFC 1982)
+func serialAfter(a, b uint32) bool {
+	return a != b && int32(a-b) > 0
+}
@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package dns
+
+import (
+	"sync"
+
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/czerwonk/atlas_exporter/exporter"
+	"github.com/czerwonk/atlas_exporter/probe"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type soaCollector struct {
+	responses     *responseCache
+	serials       map[int][]uint32
+	maxSerialDesc *prometheus.Desc
+	laggingDesc   *prometheus.Desc
+	mu            sync.Mutex
+}
+
+func newSOACollector(id, ipVersion string, cache *responseCache) exporter.ResultCollector {
+	constLabels := prometheus.Labels{
+		"measurement": id,
+		"ip_version":  ipVersion,
+	}
+
+	return &soaCollector{
+		responses:     cache,
+		serials:       make(map[int][]uint32),
+		maxSerialDesc: prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "soa_serial_max"), "Highest SOA serial seen by any probe", nil, constLabels),
+		laggingDesc:   prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "soa_lagging_probes"), "Number of probes seeing a SOA serial lower than the highest one", nil, constLabels),
+	}
+}
+
+func (c *soaCollector) ProcessResult(r *measurement.Result, p *probe.Probe) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	serials := make([]uint32, 0)
+	for _, resp := range c.responses.responses(r) {
+		if serial, found := soaSerial(resp); found {
+			serials = append(serials, serial)
+		}
+	}
+
+	if len(serials) == 0 {
+		delete(c.serials, r.PrbId())
+		return
+	}
+
+	c.serials[r.PrbId()] = serials
+}
+
+func (c *soaCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.maxSerialDesc
+	ch <- c.laggingDesc
+}
+
+func (c *soaCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.serials) == 0 {
+		return
+	}
+
+	first := true
+	var max uint32
+	for _, serials := range c.serials {
+		for _, s := range serials {
+			if first || serialAfter(s, max) {
+				max = s
+				first = false
+			}
+		}
+	}
+
+	lagging := 0
+	for _, serials := range c.serials {
+		for _, s := range serials {
+			if serialAfter(max, s) {
+				lagging++
+				break
+			}
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.maxSerialDesc, prometheus.GaugeValue, float64(max))
+	ch <- prometheus.MustNewConstMetric(c.laggingDesc, prometheus.GaugeValue, float64(lagging))
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package dns
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	mdns "github.com/miekg/dns"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSerialAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        uint32
+		b        uint32
+		expected bool
+	}{
+		{name: "greater", a: 2024010102, b: 2024010101, expected: true},
+		{name: "lower", a: 2024010101, b: 2024010102, expected: false},
+		{name: "equal", a: 42, b: 42, expected: false},
+		{name: "wraparound", a: 0, b: 0xFFFFFFFF, expected: true},
+		{name: "wraparound reverse", a: 0xFFFFFFFF, b: 0, expected: false},
+		{name: "wraparound with distance", a: 10, b: 0xFFFFFFF0, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(te *testing.T) {
+			assert.Equal(te, test.expected, serialAfter(test.a, test.b))
+		})
+	}
+}
+
+func soaMsg(serial uint32) *mdns.Msg {
+	msg := new(mdns.Msg)
+	msg.SetQuestion("example.com.", mdns.TypeSOA)
+	msg.Response = true
+	msg.Answer = []mdns.RR{&mdns.SOA{
+		Hdr:    mdns.RR_Header{Name: "example.com.", Rrtype: mdns.TypeSOA, Class: mdns.ClassINET, Ttl: 3600},
+		Ns:     "ns1.example.com.",
+		Mbox:   "hostmaster.example.com.",
+		Serial: serial,
+	}}
+
+	return msg
+}
+
+func TestSOASerial(t *testing.T) {
+	r := parseResult(t, `{"type": "dns", "result": {"rt": 1, "abuf": "`+abuf(t, soaMsg(2024010101))+`"}}`)
+	serial, found := soaSerial(responses(r)[0])
+	assert.True(t, found)
+	assert.Equal(t, uint32(2024010101), serial)
+
+	r = parseResult(t, `{"type": "dns", "result": {"rt": 1, "answers": [{"TYPE": "SOA", "NAME": "example.com.", "SERIAL": 7}]}}`)
+	serial, found = soaSerial(responses(r)[0])
+	assert.True(t, found)
+	assert.Equal(t, uint32(7), serial)
+
+	r = parseResult(t, `{"type": "dns", "error": {"timeout": 5000}}`)
+	_, found = soaSerial(responses(r)[0])
+	assert.False(t, found)
+}
+
+func TestSOACollector(t *testing.T) {
+	c := newSOACollector("1", "4", newResponseCache())
+
+	add := func(probe int, serial uint32) {
+		r := parseResult(t, `{"type": "dns", "prb_id": `+strconv.Itoa(probe)+`, "result": {"rt": 1, "abuf": "`+abuf(t, soaMsg(serial))+`"}}`)
+		c.ProcessResult(r, nil)
+	}
+
+	add(1, 0xFFFFFFFF)
+	add(2, 0)
+	add(3, 0)
+	add(3, 5)
+
+	expected := `
+# HELP atlas_dns_soa_lagging_probes Number of probes seeing a SOA serial lower than the highest one
+# TYPE atlas_dns_soa_lagging_probes gauge
+atlas_dns_soa_lagging_probes{ip_version="4",measurement="1"} 2
+# HELP atlas_dns_soa_serial_max Highest SOA serial seen by any probe
+# TYPE atlas_dns_soa_serial_max gauge
+atlas_dns_soa_serial_max{ip_version="4",measurement="1"} 5
+`
+	err := testutil.CollectAndCompare(c, strings.NewReader(expected))
+	assert.NoError(t, err)
+}
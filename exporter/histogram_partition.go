@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package exporter
+
+import (
+	"strconv"
+
+	"github.com/czerwonk/atlas_exporter/config"
+	"github.com/czerwonk/atlas_exporter/probe"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// HistogramPartition partitions histograms of a measurement by attributes of the probes
+type HistogramPartition struct {
+	by   []string
+	tags []string
+}
+
+// NewHistogramPartition returns a new instance of `HistogramPartition`
+func NewHistogramPartition(cfg config.HistogramPartition) *HistogramPartition {
+	return &HistogramPartition{
+		by:   cfg.By,
+		tags: cfg.Tags,
+	}
+}
+
+// Labels returns the names of the labels added to the histograms
+func (p *HistogramPartition) Labels() []string {
+	return p.by
+}
+
+// LabelValues returns the values of the partition labels for a probe
+func (p *HistogramPartition) LabelValues(pr *probe.Probe, af int) []string {
+	values := make([]string, len(p.by))
+	if pr == nil {
+		return values
+	}
+
+	for i, b := range p.by {
+		switch b {
+		case "country_code":
+			values[i] = pr.CountryCode
+		case "continent":
+			values[i] = pr.Continent()
+		case "asn":
+			values[i] = strconv.Itoa(pr.ASNForIPVersion(af))
+		case "tag":
+			values[i] = p.tag(pr)
+		}
+	}
+
+	return values
+}
+
+func (p *HistogramPartition) tag(pr *probe.Probe) string {
+	for _, t := range p.tags {
+		if pr.HasTag(t) {
+			return t
+		}
+	}
+
+	return ""
+}
+
+// NewHistogramVec creates a histogram partitioned by the probe attributes.
+// Without partitioning the only histogram is created immediately to be exposed before the first observation.
+func (p *HistogramPartition) NewHistogramVec(opts prometheus.HistogramOpts) *prometheus.HistogramVec {
+	h := prometheus.NewHistogramVec(opts, p.Labels())
+	if len(p.by) == 0 {
+		h.WithLabelValues()
+	}
+
+	return h
+}
+
+// NewSummaryVec creates a summary partitioned by the probe attributes.
+// Without partitioning the only summary is created immediately to be exposed before the first observation.
+func (p *HistogramPartition) NewSummaryVec(opts prometheus.SummaryOpts) *prometheus.SummaryVec {
+	s := prometheus.NewSummaryVec(opts, p.Labels())
+	if len(p.by) == 0 {
+		s.WithLabelValues()
+	}
+
+	return s
+}
@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package exporter
+
+import (
+	"testing"
+
+	"github.com/czerwonk/atlas_exporter/config"
+	"github.com/czerwonk/atlas_exporter/probe"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogramPartition(t *testing.T) {
+	p := NewHistogramPartition(config.HistogramPartition{
+		By:   []string{"country_code", "continent", "asn", "tag"},
+		Tags: []string{"datacentre", "home"},
+	})
+
+	pr := &probe.Probe{
+		ID:          1,
+		Asn4:        64496,
+		Asn6:        64497,
+		CountryCode: "au",
+		Tags: []probe.Tag{
+			{Name: "Home", Slug: "home"},
+			{Name: "IPv6 Works", Slug: "system-ipv6-works"},
+		},
+	}
+
+	assert.Equal(t, []string{"country_code", "continent", "asn", "tag"}, p.Labels())
+	assert.Equal(t, []string{"au", "OC", "64497", "home"}, p.LabelValues(pr, 6))
+	assert.Equal(t, []string{"", "", "", ""}, p.LabelValues(nil, 4))
+}
@@ -3,6 +3,8 @@
 package exporter
 
 import (
+	"sync"
+
 	"github.com/DNS-OARC/ripeatlas/measurement"
 	"github.com/czerwonk/atlas_exporter/probe"
 	"github.com/prometheus/client_golang/prometheus"
@@ -18,6 +20,13 @@ func WithHistograms(h ...Histogram) MeasurementOpt {
 	}
 }
 
+// WithCollectors adds collectors processing all results of the measurement
+func WithCollectors(c ...ResultCollector) MeasurementOpt {
+	return func(r *Measurement) {
+		r.collectors = append(r.collectors, c...)
+	}
+}
+
 // WithValidator sets an validator to validate results for a measurement
 func WithValidator(v ResultValidator) MeasurementOpt {
 	return func(r *Measurement) {
@@ -30,8 +39,10 @@ type Measurement struct {
 	latest     map[int]*measurement.Result
 	probes     map[int]*probe.Probe
 	histograms []Histogram
+	collectors []ResultCollector
 	exporter   Exporter
 	validator  ResultValidator
+	mu         sync.Mutex
 }
 
 // NewMeasurement returns a new instance of `Measurement`
@@ -40,6 +51,7 @@ func NewMeasurement(exporter Exporter, opts ...MeasurementOpt) *Measurement {
 		latest:     make(map[int]*measurement.Result),
 		probes:     make(map[int]*probe.Probe),
 		histograms: make([]Histogram, 0),
+		collectors: make([]ResultCollector, 0),
 		exporter:   exporter,
 	}
 
@@ -52,6 +64,9 @@ func NewMeasurement(exporter Exporter, opts ...MeasurementOpt) *Measurement {
 
 // Add adds an result to a measurement
 func (r *Measurement) Add(m *measurement.Result, probe *probe.Probe) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if r.validator != nil && !r.validator.IsValid(m, probe) {
 		return
 	}
@@ -60,7 +75,11 @@ func (r *Measurement) Add(m *measurement.Result, probe *probe.Probe) {
 	r.probes[m.PrbId()] = probe
 
 	for _, h := range r.histograms {
-		h.ProcessResult(m)
+		h.ProcessResult(m, probe)
+	}
+
+	for _, c := range r.collectors {
+		c.ProcessResult(m, probe)
 	}
 }
 
@@ -71,10 +90,17 @@ func (r *Measurement) Describe(ch chan<- *prometheus.Desc) {
 	for _, h := range r.histograms {
 		h.Hist().Describe(ch)
 	}
+
+	for _, c := range r.collectors {
+		c.Describe(ch)
+	}
 }
 
 // Collect collects metrics for the `Measurement`
 func (r *Measurement) Collect(ch chan<- prometheus.Metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	for _, v := range r.latest {
 		r.exporter.Export(v, r.probes[v.PrbId()], ch)
 	}
@@ -82,4 +108,8 @@ func (r *Measurement) Collect(ch chan<- prometheus.Metric) {
 	for _, h := range r.histograms {
 		h.Hist().Collect(ch)
 	}
+
+	for _, c := range r.collectors {
+		c.Collect(ch)
+	}
 }
@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package exporter
+
+import (
+	"github.com/czerwonk/atlas_exporter/config"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// WithNativeHistogram adds the options for native histograms to the options of a histogram (if enabled).
+// Classic buckets set in the options are kept, so both representations are exposed.
+func WithNativeHistogram(opts prometheus.HistogramOpts, cfg config.NativeHistogram) prometheus.HistogramOpts {
+	if !cfg.Enabled() {
+		return opts
+	}
+
+	opts.NativeHistogramBucketFactor = cfg.BucketFactor
+	opts.NativeHistogramMaxBucketNumber = cfg.MaxBuckets
+	opts.NativeHistogramZeroThreshold = cfg.ZeroThreshold
+
+	return opts
+}
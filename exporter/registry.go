@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package exporter
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/czerwonk/atlas_exporter/config"
+)
+
+// MeasurementFactory creates a new `Measurement` for a measurement of a specific type
+type MeasurementFactory func(id, ipVersion string, cfg *config.Config) *Measurement
+
+var (
+	factories   = make(map[string]MeasurementFactory)
+	factoriesMu sync.RWMutex
+)
+
+// Register registers the factory for a measurement type. Registering a factory for a type already known replaces
+// the existing one, so custom binaries can override built-in exporters (e.g. in an init function of package main).
+func Register(measurementType string, f MeasurementFactory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	factories[measurementType] = f
+}
+
+// NewMeasurementForType creates a new `Measurement` by using the factory registered for the type
+func NewMeasurementForType(measurementType, id, ipVersion string, cfg *config.Config) (*Measurement, error) {
+	factoriesMu.RLock()
+	f, found := factories[measurementType]
+	factoriesMu.RUnlock()
+
+	if !found {
+		return nil, fmt.Errorf("type %s is not supported yet", measurementType)
+	}
+
+	return f(id, ipVersion, cfg), nil
+}
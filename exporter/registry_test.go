@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package exporter
+
+import (
+	"testing"
+
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/czerwonk/atlas_exporter/config"
+	"github.com/czerwonk/atlas_exporter/probe"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+type testExporter struct {
+	name string
+}
+
+func (e *testExporter) Export(res *measurement.Result, probe *probe.Probe, ch chan<- prometheus.Metric) {
+}
+
+func (e *testExporter) Describe(ch chan<- *prometheus.Desc) {
+}
+
+func factoryFor(name string) MeasurementFactory {
+	return func(id, ipVersion string, cfg *config.Config) *Measurement {
+		return NewMeasurement(&testExporter{name: name})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	t.Cleanup(func() {
+		factoriesMu.Lock()
+		defer factoriesMu.Unlock()
+
+		delete(factories, "registry-test")
+	})
+
+	_, err := NewMeasurementForType("registry-test", "1", "4", &config.Config{})
+	assert.Error(t, err)
+
+	Register("registry-test", factoryFor("builtin"))
+	m, err := NewMeasurementForType("registry-test", "1", "4", &config.Config{})
+	assert.NoError(t, err)
+	assert.Equal(t, "builtin", m.exporter.(*testExporter).name)
+
+	Register("registry-test", factoryFor("custom"))
+	m, err = NewMeasurementForType("registry-test", "1", "4", &config.Config{})
+	assert.NoError(t, err)
+	assert.Equal(t, "custom", m.exporter.(*testExporter).name)
+}
@@ -0,0 +1,17 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package exporter
+
+import (
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/czerwonk/atlas_exporter/probe"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ResultCollector collects metrics derived from all results added to a measurement (e.g. aggregates over probes)
+type ResultCollector interface {
+	prometheus.Collector
+
+	// ProcessResult updates the state of the collector with a result
+	ProcessResult(res *measurement.Result, probe *probe.Probe)
+}
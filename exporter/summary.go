@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package exporter
+
+import (
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/czerwonk/atlas_exporter/config"
+	"github.com/czerwonk/atlas_exporter/probe"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var defaultObjectives = []float64{0.5, 0.9, 0.99}
+
+// Value is a value of a result (e.g. a round trip time) with the address family of the request it was measured for
+type Value struct {
+	Value float64
+	Af    int
+}
+
+// ValuesFunc extracts the values of a result
+type ValuesFunc func(*measurement.Result) []Value
+
+// ResultValues returns a `ValuesFunc` using the address family of the result for all values returned by `values`
+func ResultValues(values func(*measurement.Result) []float64) ValuesFunc {
+	return func(r *measurement.Result) []Value {
+		res := make([]Value, 0)
+		for _, v := range values(r) {
+			res = append(res, Value{Value: v, Af: r.Af()})
+		}
+
+		return res
+	}
+}
+
+// summary calculates quantiles of the values of a measurement (e.g. round trip times) within a sliding window
+type summary struct {
+	summary   *prometheus.SummaryVec
+	partition *HistogramPartition
+	values    ValuesFunc
+}
+
+// NewRttSummary returns a new summary of the round trip times of a measurement with the given fully qualified name
+func NewRttSummary(name, help, id, ipVersion string, cfg config.Summary, partition *HistogramPartition, values ValuesFunc) Histogram {
+	return NewSummary(prometheus.SummaryOpts{
+		Name: name,
+		Help: help,
+		ConstLabels: prometheus.Labels{
+			"measurement": id,
+			"ip_version":  ipVersion,
+		},
+	}, cfg, partition, values)
+}
+
+// NewSummary returns a new `Histogram` calculating quantiles of the values returned by `values` for each result.
+// Values are only considered as long as they are not older than the max age defined in the config.
+func NewSummary(opts prometheus.SummaryOpts, cfg config.Summary, partition *HistogramPartition, values ValuesFunc) Histogram {
+	opts.MaxAge = cfg.MaxAge
+	opts.Objectives = objectives(cfg.Objectives)
+
+	return &summary{
+		summary:   partition.NewSummaryVec(opts),
+		partition: partition,
+		values:    values,
+	}
+}
+
+// objectives returns the objectives with an absolute error of 1/10 of the distance to 1 (e.g. 0.99 -> 0.001)
+func objectives(quantiles []float64) map[float64]float64 {
+	if len(quantiles) == 0 {
+		quantiles = defaultObjectives
+	}
+
+	obj := make(map[float64]float64, len(quantiles))
+	for _, q := range quantiles {
+		obj[q] = (1 - q) / 10
+	}
+
+	return obj
+}
+
+func (s *summary) ProcessResult(r *measurement.Result, probe *probe.Probe) {
+	for _, v := range s.values(r) {
+		s.summary.WithLabelValues(s.partition.LabelValues(probe, v.Af)...).Observe(v.Value)
+	}
+}
+
+func (s *summary) Hist() prometheus.Collector {
+	return s.summary
+}
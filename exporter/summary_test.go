@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package exporter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/czerwonk/atlas_exporter/config"
+	"github.com/czerwonk/atlas_exporter/probe"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObjectives(t *testing.T) {
+	assert.InDeltaMapValues(t, map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001}, objectives(nil), 1e-9)
+	assert.InDeltaMapValues(t, map[float64]float64{0.95: 0.005}, objectives([]float64{0.95}), 1e-9)
+}
+
+func TestSummary(t *testing.T) {
+	values := func(r *measurement.Result) []float64 {
+		return []float64{1, 2, 3}
+	}
+
+	s := NewSummary(prometheus.SummaryOpts{Name: "rtt_summary", Help: "test"}, config.Summary{MaxAge: time.Hour}, NewHistogramPartition(config.HistogramPartition{}), ResultValues(values))
+	s.ProcessResult(&measurement.Result{}, nil)
+	s.ProcessResult(&measurement.Result{}, nil)
+
+	assert.Equal(t, 1, testutil.CollectAndCount(s.Hist()))
+}
+
+func TestRttSummaryPartitionsByValueAf(t *testing.T) {
+	values := func(r *measurement.Result) []Value {
+		return []Value{{Value: 1, Af: 4}, {Value: 2, Af: 6}, {Value: 3, Af: 6}}
+	}
+
+	partition := NewHistogramPartition(config.HistogramPartition{By: []string{"asn"}})
+	s := NewRttSummary("atlas_dns_rtt_summary", "test", "1", "4", config.Summary{MaxAge: time.Hour, Objectives: []float64{0.5}}, partition, values)
+	s.ProcessResult(&measurement.Result{}, &probe.Probe{ID: 1, Asn4: 64496, Asn6: 64497})
+
+	expected := `
+# HELP atlas_dns_rtt_summary test
+# TYPE atlas_dns_rtt_summary summary
+atlas_dns_rtt_summary{asn="64496",ip_version="4",measurement="1",quantile="0.5"} 1
+atlas_dns_rtt_summary_sum{asn="64496",ip_version="4",measurement="1"} 1
+atlas_dns_rtt_summary_count{asn="64496",ip_version="4",measurement="1"} 1
+atlas_dns_rtt_summary{asn="64497",ip_version="4",measurement="1",quantile="0.5"} 2
+atlas_dns_rtt_summary_sum{asn="64497",ip_version="4",measurement="1"} 5
+atlas_dns_rtt_summary_count{asn="64497",ip_version="4",measurement="1"} 2
+`
+	assert.NoError(t, testutil.CollectAndCompare(s.Hist(), strings.NewReader(expected)))
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/DNS-OARC/ripeatlas/measurement"
 	"github.com/czerwonk/atlas_exporter/probe"
 	"github.com/prometheus/client_golang/prometheus"
-	log "github.com/sirupsen/logrus"
 )
 
 var (
@@ -20,22 +19,33 @@ var (
 	rttDesc        *prometheus.Desc
 	dnsErrDesc     *prometheus.Desc
 	successDesc    *prometheus.Desc
+	ttrDesc        *prometheus.Desc
+	ttcDesc        *prometheus.Desc
+	ttfbDesc       *prometheus.Desc
+	readTimeDesc   *prometheus.Desc
+	errorDesc      *prometheus.Desc
 )
 
 func init() {
 	labels = []string{"measurement", "probe", "dst_addr", "asn", "ip_version", "uri", "method", "country_code", "lat", "long"}
 
-	successDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "success"), "Destination was reachable", labels, nil)
+	successDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "success"), "Destination was reachable and returned an acceptable status code", labels, nil)
+	errorDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "error"), "Request failed for the given reason", append(labels, "reason"), nil)
 	resultDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "result"), "Code returned from http server", labels, nil)
-	httpVerDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "version"), "HTTP version used for the request", labels, nil)
+	httpVerDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "version_info"), "HTTP version used for the request (none if no response was received)", append(labels, "version"), nil)
 	bodySizeDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "body_size"), "Body size in bytes", labels, nil)
 	headerSizeDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "header_size"), "Header size in bytes", labels, nil)
 	rttDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "rtt"), "Round trip time in ms", labels, nil)
 	dnsErrDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "dns_error"), "A DNS error occurred (0 if not)", labels, nil)
+	ttrDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "ttr"), "Time to resolve the DNS name in ms", labels, nil)
+	ttcDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "ttc"), "Time to connect to the target in ms", labels, nil)
+	ttfbDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "ttfb"), "Time to first response byte after starting to connect in ms", labels, nil)
+	readTimeDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "read_time"), "Time until the last data was received after starting to connect in ms (requires readtiming)", labels, nil)
 }
 
 type httpExporter struct {
-	id string
+	id     string
+	status *statusChecker
 }
 
 // Export exports metrics for Prometheus
@@ -59,23 +69,37 @@ func (m *httpExporter) Export(res *measurement.Result, probe *probe.Probe, ch ch
 			dnsError = 1
 		}
 
-		httpVer, err := strconv.ParseFloat(h.Ver(), 64)
-		if err != nil {
-			log.Errorf("error parsing http version %s: %v", h.Ver(), err)
-		}
-
 		ch <- prometheus.MustNewConstMetric(resultDesc, prometheus.GaugeValue, float64(h.Res()), labelValues...)
-		ch <- prometheus.MustNewConstMetric(httpVerDesc, prometheus.GaugeValue, httpVer, labelValues...)
+		ch <- prometheus.MustNewConstMetric(httpVerDesc, prometheus.GaugeValue, 1, append(labelValues, protocolVersion(h))...)
 		ch <- prometheus.MustNewConstMetric(bodySizeDesc, prometheus.GaugeValue, float64(h.Bsize()), labelValues...)
 		ch <- prometheus.MustNewConstMetric(headerSizeDesc, prometheus.GaugeValue, float64(h.Hsize()), labelValues...)
 		ch <- prometheus.MustNewConstMetric(dnsErrDesc, prometheus.GaugeValue, float64(dnsError), labelValues...)
 
-		if h.Rt() > 0 {
+		timings := map[*prometheus.Desc]float64{
+			ttrDesc:      h.Ttr(),
+			ttcDesc:      h.Ttc(),
+			ttfbDesc:     h.Ttfb(),
+			readTimeDesc: readTime(h),
+		}
+		for d, v := range timings {
+			if v > 0 {
+				ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, v, labelValues...)
+			}
+		}
+
+		if m.status.success(h) {
 			ch <- prometheus.MustNewConstMetric(successDesc, prometheus.GaugeValue, 1, labelValues...)
-			ch <- prometheus.MustNewConstMetric(rttDesc, prometheus.GaugeValue, h.Rt(), labelValues...)
 		} else {
 			ch <- prometheus.MustNewConstMetric(successDesc, prometheus.GaugeValue, 0, labelValues...)
 		}
+
+		if h.Rt() > 0 {
+			ch <- prometheus.MustNewConstMetric(rttDesc, prometheus.GaugeValue, h.Rt(), labelValues...)
+		}
+
+		if reason := errorReason(h); len(reason) > 0 {
+			ch <- prometheus.MustNewConstMetric(errorDesc, prometheus.GaugeValue, 1, append(labelValues, reason)...)
+		}
 	}
 }
 
@@ -88,4 +112,9 @@ func (m *httpExporter) Describe(ch chan<- *prometheus.Desc) {
 	ch <- headerSizeDesc
 	ch <- rttDesc
 	ch <- dnsErrDesc
+	ch <- ttrDesc
+	ch <- ttcDesc
+	ch <- ttfbDesc
+	ch <- readTimeDesc
+	ch <- errorDesc
 }
@@ -5,6 +5,7 @@ package http
 import (
 	"github.com/czerwonk/atlas_exporter/config"
 	"github.com/czerwonk/atlas_exporter/exporter"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 const (
@@ -12,15 +13,36 @@ const (
 	sub = "http"
 )
 
+func init() {
+	exporter.Register("http", NewMeasurement)
+}
+
 // NewMeasurement returns a new instance of `exorter.Measurement` for a HTTP measurement
 func NewMeasurement(id, ipVersion string, cfg *config.Config) *exporter.Measurement {
+	m, _ := cfg.MeasurementByID(id)
+	buckets := cfg.HistogramBucketsForMeasurement(id)
+	timingBuckets := buckets.HTTP.Timing
+	if timingBuckets == nil {
+		timingBuckets = buckets.HTTP.Rtt
+	}
+
+	partition := exporter.NewHistogramPartition(cfg.HistogramPartition)
 	opts := []exporter.MeasurementOpt{
-		exporter.WithHistograms(newRttHistogram(id, ipVersion, cfg.HistogramBuckets.HTTP.Rtt)),
+		exporter.WithHistograms(newRttHistogram(id, ipVersion, buckets.HTTP.Rtt, buckets.HTTP.Native, partition)),
+		exporter.WithHistograms(newTimingHistograms(id, ipVersion, timingBuckets, buckets.HTTP.Native, partition)...),
+		exporter.WithCollectors(newStatusCollector(id, ipVersion)),
+	}
+
+	if m.Summary.Enabled() {
+		opts = append(opts, exporter.WithHistograms(exporter.NewRttSummary(prometheus.BuildFQName(ns, sub, "rtt_summary"),
+			"Summary of round trip times over all HTTP requests within a sliding window", id, ipVersion, m.Summary, partition, exporter.ResultValues(rtts))))
 	}
 
 	if cfg.FilterInvalidResults {
 		opts = append(opts, exporter.WithValidator(&exporter.DefaultResultValidator{}))
 	}
 
-	return exporter.NewMeasurement(&httpExporter{id}, opts...)
+	status := &statusChecker{acceptable: m.HTTP.AcceptableStatusCodes}
+
+	return exporter.NewMeasurement(&httpExporter{id: id, status: status}, opts...)
 }
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package http
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/DNS-OARC/ripeatlas/measurement/http"
+)
+
+const (
+	errorDNS               = "dns"
+	errorTimeout           = "timeout"
+	errorConnectionRefused = "connection_refused"
+	errorConnectionReset   = "connection_reset"
+	errorUnreachable       = "unreachable"
+	errorTLS               = "tls"
+	errorOther             = "other"
+)
+
+// statusChecker decides whether a response is considered successful
+type statusChecker struct {
+	acceptable []int
+}
+
+func (c *statusChecker) success(r *http.Result) bool {
+	if r.Rt() <= 0 || len(r.Err()) > 0 || len(r.Dnserr()) > 0 {
+		return false
+	}
+
+	if len(c.acceptable) == 0 {
+		return r.Res() >= 200 && r.Res() < 400
+	}
+
+	return slices.Contains(c.acceptable, r.Res())
+}
+
+// statusClass returns the class of the status code (e.g. 2xx) or "none" if no response was received
+func statusClass(r *http.Result) string {
+	if r.Res() < 100 || r.Res() > 599 {
+		return "none"
+	}
+
+	return fmt.Sprintf("%dxx", r.Res()/100)
+}
+
+// errorPatterns maps phrases of errors reported by the probes to reasons. The phrases are taken from the error
+// messages of the HTTP measurement implementation on the probes (e.g. "connect: Connection refused",
+// "timeout reading chunk"), which are free text. Errors not matching any phrase are reported as other.
+var errorPatterns = []struct {
+	phrase string
+	reason string
+}{
+	{phrase: "timeout", reason: errorTimeout},
+	{phrase: "timed out", reason: errorTimeout},
+	{phrase: "connection refused", reason: errorConnectionRefused},
+	{phrase: "connection reset", reason: errorConnectionReset},
+	{phrase: "network is unreachable", reason: errorUnreachable},
+	{phrase: "host is unreachable", reason: errorUnreachable},
+	{phrase: "no route to host", reason: errorUnreachable},
+	{phrase: "ssl", reason: errorTLS},
+	{phrase: "tls", reason: errorTLS},
+}
+
+// errorReason derives the reason of a failed request from the error reported by the probe (empty if no error occurred)
+func errorReason(r *http.Result) string {
+	if len(r.Dnserr()) > 0 {
+		return errorDNS
+	}
+
+	if len(r.Err()) == 0 {
+		return ""
+	}
+
+	e := strings.ToLower(r.Err())
+	for _, p := range errorPatterns {
+		if strings.Contains(e, p.phrase) {
+			return p.reason
+		}
+	}
+
+	return errorOther
+}
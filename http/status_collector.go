@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package http
+
+import (
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/czerwonk/atlas_exporter/exporter"
+	"github.com/czerwonk/atlas_exporter/probe"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type statusCollector struct {
+	responses *prometheus.CounterVec
+}
+
+func newStatusCollector(id, ipVersion string) exporter.ResultCollector {
+	return &statusCollector{
+		responses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Subsystem: sub,
+			Name:      "responses_total",
+			Help:      "Number of responses by status class (none if no response was received)",
+			ConstLabels: prometheus.Labels{
+				"measurement": id,
+				"ip_version":  ipVersion,
+			},
+		}, []string{"status_class"}),
+	}
+}
+
+func (c *statusCollector) ProcessResult(r *measurement.Result, p *probe.Probe) {
+	for _, h := range r.HttpResults() {
+		c.responses.WithLabelValues(statusClass(h)).Inc()
+	}
+}
+
+func (c *statusCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.responses.Describe(ch)
+}
+
+func (c *statusCollector) Collect(ch chan<- prometheus.Metric) {
+	c.responses.Collect(ch)
+}
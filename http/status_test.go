@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package http
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusCheckerSuccess(t *testing.T) {
+	tests := []struct {
+		name       string
+		result     string
+		acceptable []int
+		expected   bool
+	}{
+		{name: "199", result: `{"rt": 10, "res": 199}`, expected: false},
+		{name: "200", result: `{"rt": 10, "res": 200}`, expected: true},
+		{name: "399", result: `{"rt": 10, "res": 399}`, expected: true},
+		{name: "400", result: `{"rt": 10, "res": 400}`, expected: false},
+		{name: "599", result: `{"rt": 10, "res": 599}`, expected: false},
+		{name: "no response", result: `{"rt": 10, "res": 0}`, expected: false},
+		{name: "no rtt", result: `{"res": 200}`, expected: false},
+		{name: "error", result: `{"rt": 10, "res": 200, "err": "timeout reading chunk"}`, expected: false},
+		{name: "dns error", result: `{"res": 200, "dnserr": "non-recoverable failure in name resolution (-4)"}`, expected: false},
+		{name: "acceptable 404", result: `{"rt": 10, "res": 404}`, acceptable: []int{200, 404}, expected: true},
+		{name: "not acceptable 301", result: `{"rt": 10, "res": 301}`, acceptable: []int{200, 404}, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(te *testing.T) {
+			r := parseResult(te, `{"type": "http", "result": [`+test.result+`]}`)
+			c := &statusChecker{acceptable: test.acceptable}
+			assert.Equal(te, test.expected, c.success(r.HttpResults()[0]))
+		})
+	}
+}
+
+func TestStatusClass(t *testing.T) {
+	tests := []struct {
+		res      int
+		expected string
+	}{
+		{res: 0, expected: "none"},
+		{res: 99, expected: "none"},
+		{res: 100, expected: "1xx"},
+		{res: 199, expected: "1xx"},
+		{res: 200, expected: "2xx"},
+		{res: 399, expected: "3xx"},
+		{res: 400, expected: "4xx"},
+		{res: 599, expected: "5xx"},
+		{res: 600, expected: "none"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(te *testing.T) {
+			r := parseResult(te, `{"type": "http", "result": [{"rt": 10, "res": `+strconv.Itoa(test.res)+`}]}`)
+			assert.Equal(te, test.expected, statusClass(r.HttpResults()[0]))
+		})
+	}
+}
+
+func TestErrorReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   string
+		expected string
+	}{
+		{name: "no error", result: `{"rt": 10, "res": 200}`, expected: ""},
+		{name: "dns error", result: `{"dnserr": "non-recoverable failure in name resolution (-4)"}`, expected: errorDNS},
+		{name: "connect timeout", result: `{"err": "connect: timeout"}`, expected: errorTimeout},
+		{name: "timeout reading chunk", result: `{"err": "timeout reading chunk"}`, expected: errorTimeout},
+		{name: "connection timed out", result: `{"err": "connect: Connection timed out"}`, expected: errorTimeout},
+		{name: "connection refused", result: `{"err": "connect: Connection refused"}`, expected: errorConnectionRefused},
+		{name: "connection reset", result: `{"err": "read error: Connection reset by peer"}`, expected: errorConnectionReset},
+		{name: "network unreachable", result: `{"err": "connect: Network is unreachable"}`, expected: errorUnreachable},
+		{name: "no route to host", result: `{"err": "connect: No route to host"}`, expected: errorUnreachable},
+		{name: "tls", result: `{"err": "SSL_connect: certificate verify failed"}`, expected: errorTLS},
+		{name: "unknown", result: `{"err": "bad status line"}`, expected: errorOther},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(te *testing.T) {
+			r := parseResult(te, `{"type": "http", "result": [`+test.result+`]}`)
+			assert.Equal(te, test.expected, errorReason(r.HttpResults()[0]))
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package http
+
+import (
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/DNS-OARC/ripeatlas/measurement/http"
+	"github.com/czerwonk/atlas_exporter/config"
+	"github.com/czerwonk/atlas_exporter/exporter"
+	"github.com/czerwonk/atlas_exporter/probe"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// timingHistogram is a histogram of the duration of a single phase of HTTP requests
+type timingHistogram struct {
+	hist      *prometheus.HistogramVec
+	partition *exporter.HistogramPartition
+	value     func(*http.Result) float64
+}
+
+func newTimingHistograms(id, ipVersion string, buckets []float64, native config.NativeHistogram, partition *exporter.HistogramPartition) []exporter.Histogram {
+	if buckets == nil {
+		buckets = []float64{10, 50, 100, 200, 500, 1000}
+	}
+
+	return []exporter.Histogram{
+		newTimingHistogram(id, ipVersion, buckets, native, partition, "ttr_hist", "Histogram of times to resolve the DNS name over all HTTP requests", (*http.Result).Ttr),
+		newTimingHistogram(id, ipVersion, buckets, native, partition, "ttc_hist", "Histogram of times to connect over all HTTP requests", (*http.Result).Ttc),
+		newTimingHistogram(id, ipVersion, buckets, native, partition, "ttfb_hist", "Histogram of times to first byte over all HTTP requests", (*http.Result).Ttfb),
+		newTimingHistogram(id, ipVersion, buckets, native, partition, "read_time_hist", "Histogram of times until the last data was received over all HTTP requests (requires readtiming)", readTime),
+	}
+}
+
+func newTimingHistogram(id, ipVersion string, buckets []float64, native config.NativeHistogram, partition *exporter.HistogramPartition, name, help string, value func(*http.Result) float64) exporter.Histogram {
+	return &timingHistogram{
+		value:     value,
+		partition: partition,
+		hist: partition.NewHistogramVec(exporter.WithNativeHistogram(prometheus.HistogramOpts{
+			Namespace: ns,
+			Subsystem: sub,
+			Name:      name,
+			Buckets:   buckets,
+			Help:      help,
+			ConstLabels: prometheus.Labels{
+				"measurement": id,
+				"ip_version":  ipVersion,
+			},
+		}, native)),
+	}
+}
+
+func (h *timingHistogram) ProcessResult(r *measurement.Result, probe *probe.Probe) {
+	hist := h.hist.WithLabelValues(h.partition.LabelValues(probe, r.Af())...)
+	for _, p := range r.HttpResults() {
+		if v := h.value(p); v > 0 {
+			hist.Observe(v)
+		}
+	}
+}
+
+func (h *timingHistogram) Hist() prometheus.Collector {
+	return h.hist
+}
+
+// readTime returns the time since starting to connect until the last data was received
+// (0 if readtiming is not present or contains no valid timing)
+func readTime(r *http.Result) float64 {
+	var max float64
+	for _, t := range r.Readtimings() {
+		if t.T() > max {
+			max = t.T()
+		}
+	}
+
+	return max
+}
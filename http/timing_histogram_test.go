@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package http
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/czerwonk/atlas_exporter/config"
+	"github.com/czerwonk/atlas_exporter/exporter"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func parseResult(t *testing.T, s string) *measurement.Result {
+	var r measurement.Result
+	err := json.Unmarshal([]byte(s), &r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &r
+}
+
+func TestReadTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   string
+		expected float64
+	}{
+		{
+			name:     "readtiming not present",
+			result:   `{"rt": 10}`,
+			expected: 0,
+		},
+		{
+			name:     "empty readtiming",
+			result:   `{"rt": 10, "readtiming": []}`,
+			expected: 0,
+		},
+		{
+			name:     "zero timing",
+			result:   `{"rt": 10, "readtiming": [{"o": "0", "t": 0}]}`,
+			expected: 0,
+		},
+		{
+			name:     "negative timing",
+			result:   `{"rt": 10, "readtiming": [{"o": "0", "t": -1}]}`,
+			expected: 0,
+		},
+		{
+			name:     "multiple timings",
+			result:   `{"rt": 10, "readtiming": [{"o": "0", "t": 20.5}, {"o": "1024", "t": 31.2}, {"o": "2048", "t": 30.1}]}`,
+			expected: 31.2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(te *testing.T) {
+			r := parseResult(te, `{"type": "http", "result": [`+test.result+`]}`)
+			assert.Equal(te, test.expected, readTime(r.HttpResults()[0]))
+		})
+	}
+}
+
+func TestTimingHistogramIgnoresMissingTimings(t *testing.T) {
+	partition := exporter.NewHistogramPartition(config.HistogramPartition{})
+	hists := newTimingHistograms("1", "4", nil, config.NativeHistogram{}, partition)
+
+	r := parseResult(t, `{"type": "http", "result": [
+  {"rt": 10, "ttc": 0, "ttr": -1, "ttfb": 12.5},
+  {"rt": -1, "err": "timeout reading chunk"}
+]}`)
+
+	for _, h := range hists {
+		h.ProcessResult(r, nil)
+	}
+
+	// order of histograms: ttr, ttc, ttfb, read time
+	expectedCounts := []uint64{0, 0, 1, 0}
+	for i, h := range hists {
+		assert.Equal(t, expectedCounts[i], sampleCount(t, h.Hist()))
+	}
+}
+
+func sampleCount(t *testing.T, c prometheus.Collector) uint64 {
+	ch := make(chan prometheus.Metric, 1)
+	c.Collect(ch)
+	close(ch)
+
+	var m dto.Metric
+	err := (<-ch).Write(&m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return m.GetHistogram().GetSampleCount()
+}
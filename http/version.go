@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package http
+
+import (
+	"strings"
+
+	"github.com/DNS-OARC/ripeatlas/measurement/http"
+)
+
+const (
+	// versionNone is used for requests without response (e.g. failed requests)
+	versionNone = "none"
+	// versionUnknown is used for versions not known to the exporter
+	versionUnknown = "unknown"
+)
+
+// protocolVersion returns the normalized HTTP version of a response (1.0, 1.1, 2, 3, none or unknown)
+func protocolVersion(r *http.Result) string {
+	v := strings.TrimSpace(r.Ver())
+	v = strings.TrimPrefix(strings.ToUpper(v), "HTTP/")
+
+	switch v {
+	case "":
+		return versionNone
+	case "1", "1.0":
+		return "1.0"
+	case "1.1":
+		return "1.1"
+	case "2", "2.0":
+		return "2"
+	case "3", "3.0":
+		return "3"
+	}
+
+	return versionUnknown
+}
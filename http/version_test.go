@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package http
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtocolVersion(t *testing.T) {
+	tests := []struct {
+		ver      string
+		expected string
+	}{
+		{ver: "1.0", expected: "1.0"},
+		{ver: "1", expected: "1.0"},
+		{ver: "1.1", expected: "1.1"},
+		{ver: "HTTP/1.1", expected: "1.1"},
+		{ver: "2", expected: "2"},
+		{ver: "2.0", expected: "2"},
+		{ver: "http/2", expected: "2"},
+		{ver: "3", expected: "3"},
+		{ver: "", expected: versionNone},
+		{ver: " ", expected: versionNone},
+		{ver: "0.9", expected: versionUnknown},
+		{ver: "foo", expected: versionUnknown},
+	}
+
+	for _, test := range tests {
+		t.Run(test.ver, func(te *testing.T) {
+			r := parseResult(te, `{"type": "http", "result": [{"rt": 10, "res": 200, "ver": "`+test.ver+`"}]}`)
+			assert.Equal(te, test.expected, protocolVersion(r.HttpResults()[0]))
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 const (
 	generalTimeout = 60 * time.Second
 	streamTimeout  = 5 * time.Minute
+	pollInterval   = 5 * time.Minute
 	version        = "1.0.4"
 )
 
@@ -39,6 +40,9 @@ var (
 	streaming           = flag.Bool("streaming", true, "Retrieve data by subscribing to Atlas Streaming API")
 	streamingBufferSize = flag.Uint("streaming.buffer-size", 100, "Size of buffer to prevent locking socket.io go routines")
 	streamingTimeout    = flag.Duration("streaming.timeout", streamTimeout, "When no update is received in this timespan a reconnect is initiated.")
+	streamingFallback   = flag.Uint("streaming.fallback-after", 0, "Number of failed connections to the Streaming API after which results are polled until streaming recovers (0 = disabled)")
+	polling             = flag.Bool("polling", false, "Retrieve data by polling results from Atlas API in the background (takes precedence over streaming)")
+	pollingInterval     = flag.Duration("polling.interval", pollInterval, "Interval for polling new results of a measurement")
 	profiling           = flag.Bool("profiling", false, "Enables pprof endpoints")
 	goMetrics           = flag.Bool("metrics.go", true, "Enables go runtime prometheus metrics")
 	processMetrics      = flag.Bool("metrics.process", true, "Enables process runtime prometheus metrics")
@@ -74,11 +78,17 @@ func main() {
 		os.Exit(1)
 	}
 
-	if *streaming {
-		ctx, cancel := context.WithCancel(context.Background())
-		defer cancel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	switch {
+	case *polling:
+		strategy = atlas.NewPollingStrategy(ctx, cfg, *workerCount, *pollingInterval)
+	case *streaming && *streamingFallback > 0:
+		strategy = atlas.NewHybridStrategy(ctx, cfg, *streamingBufferSize, *workerCount, *streamingTimeout, *streamingFallback, *pollingInterval)
+	case *streaming:
 		strategy = atlas.NewStreamingStrategy(ctx, cfg, *streamingBufferSize, *streamingTimeout)
-	} else {
+	default:
 		strategy = atlas.NewRequestStrategy(cfg, *workerCount)
 	}
 
@@ -204,6 +214,10 @@ func handleMetricsRequest(w http.ResponseWriter, r *http.Request) error {
 		reg.MustRegister(c)
 	}
 
+	if c, ok := s.(prometheus.Collector); ok {
+		reg.MustRegister(c)
+	}
+
 	l := log.New()
 	l.Level = log.ErrorLevel
 
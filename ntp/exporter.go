@@ -12,21 +12,40 @@ import (
 
 var (
 	labels             []string
+	successDesc        *prometheus.Desc
+	errorDesc          *prometheus.Desc
 	pollDesc           *prometheus.Desc
 	precisionDesc      *prometheus.Desc
 	roolDelayDesc      *prometheus.Desc
 	rootDispersionDesc *prometheus.Desc
 	ntpVersionDesc     *prometheus.Desc
+	stratumDesc        *prometheus.Desc
+	infoDesc           *prometheus.Desc
+	offsetDesc         *prometheus.Desc
+	rttDesc            *prometheus.Desc
+	requestDelayDesc   *prometheus.Desc
+	serverTimeDesc     *prometheus.Desc
+	responseDelayDesc  *prometheus.Desc
 )
 
 func init() {
 	labels = []string{"measurement", "probe", "dst_addr", "dst_name", "asn", "ip_version", "country_code", "lat", "long"}
+	packetLabels := append(labels, "packet")
 
+	successDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "success"), "Server replied with a valid and synchronized response", labels, nil)
+	errorDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "error"), "Query failed for the given reason (timeout, malformed, kiss_of_death, unsynchronized)", append(labels, "reason"), nil)
 	pollDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "poll"), "Poll", labels, nil)
 	precisionDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "precision"), "Precision", labels, nil)
 	roolDelayDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "root_delay"), "Root delay", labels, nil)
 	rootDispersionDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "root_dispersion"), "Root dispersion", labels, nil)
 	ntpVersionDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "ntp_version"), "NTP Version", labels, nil)
+	stratumDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "stratum"), "Stratum of the server", labels, nil)
+	infoDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "info"), "Reference ID, leap indicator and mode reported by the server", append(labels, "ref_id", "leap_indicator", "mode"), nil)
+	offsetDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "offset_seconds"), "Clock offset between probe and server", packetLabels, nil)
+	rttDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "rtt_seconds"), "Round trip time between probe and server", packetLabels, nil)
+	requestDelayDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "request_delay_seconds"), "Time between sending the request and the server receiving it (receive - origin timestamp, includes clock offset)", packetLabels, nil)
+	serverTimeDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "server_processing_seconds"), "Time between the server receiving the request and sending the response (transmit - receive timestamp)", packetLabels, nil)
+	responseDelayDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "response_delay_seconds"), "Time between the server sending the response and the probe receiving it (final - transmit timestamp, includes clock offset)", packetLabels, nil)
 }
 
 type ntpExporter struct {
@@ -47,18 +66,54 @@ func (m *ntpExporter) Export(res *measurement.Result, probe *probe.Probe, ch cha
 		probe.Longitude(),
 	}
 
+	reason := errorReason(res)
+	if len(reason) == 0 {
+		ch <- prometheus.MustNewConstMetric(successDesc, prometheus.GaugeValue, 1, labelValues...)
+	} else {
+		ch <- prometheus.MustNewConstMetric(successDesc, prometheus.GaugeValue, 0, labelValues...)
+		ch <- prometheus.MustNewConstMetric(errorDesc, prometheus.GaugeValue, 1, append(labelValues, reason)...)
+	}
+
+	if reason == errorTimeout || reason == errorMalformed {
+		return
+	}
+
 	ch <- prometheus.MustNewConstMetric(pollDesc, prometheus.GaugeValue, res.Poll(), labelValues...)
 	ch <- prometheus.MustNewConstMetric(precisionDesc, prometheus.GaugeValue, res.Precision(), labelValues...)
 	ch <- prometheus.MustNewConstMetric(roolDelayDesc, prometheus.GaugeValue, res.RootDelay(), labelValues...)
 	ch <- prometheus.MustNewConstMetric(rootDispersionDesc, prometheus.GaugeValue, res.RootDispersion(), labelValues...)
 	ch <- prometheus.MustNewConstMetric(ntpVersionDesc, prometheus.GaugeValue, float64(res.Version()), labelValues...)
+	ch <- prometheus.MustNewConstMetric(stratumDesc, prometheus.GaugeValue, float64(res.Stratum()), labelValues...)
+	ch <- prometheus.MustNewConstMetric(infoDesc, prometheus.GaugeValue, 1, append(labelValues, res.RefId(), res.Li(), res.Mode())...)
+
+	for i, p := range res.NtpResults() {
+		if !validReply(p) {
+			continue
+		}
+
+		packetLabelValues := append(labelValues, strconv.Itoa(i))
+		ch <- prometheus.MustNewConstMetric(offsetDesc, prometheus.GaugeValue, p.Offset(), packetLabelValues...)
+		ch <- prometheus.MustNewConstMetric(rttDesc, prometheus.GaugeValue, p.Rtt(), packetLabelValues...)
+		ch <- prometheus.MustNewConstMetric(requestDelayDesc, prometheus.GaugeValue, p.ReceiveTs()-p.OriginTs(), packetLabelValues...)
+		ch <- prometheus.MustNewConstMetric(serverTimeDesc, prometheus.GaugeValue, p.TransmitTs()-p.ReceiveTs(), packetLabelValues...)
+		ch <- prometheus.MustNewConstMetric(responseDelayDesc, prometheus.GaugeValue, p.FinalTs()-p.TransmitTs(), packetLabelValues...)
+	}
 }
 
 // Describe exports metric descriptions for Prometheus
 func (m *ntpExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- successDesc
+	ch <- errorDesc
 	ch <- pollDesc
 	ch <- precisionDesc
 	ch <- roolDelayDesc
 	ch <- rootDispersionDesc
 	ch <- ntpVersionDesc
+	ch <- stratumDesc
+	ch <- infoDesc
+	ch <- offsetDesc
+	ch <- rttDesc
+	ch <- requestDelayDesc
+	ch <- serverTimeDesc
+	ch <- responseDelayDesc
 }
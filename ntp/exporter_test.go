@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package ntp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/czerwonk/atlas_exporter/config"
+	"github.com/czerwonk/atlas_exporter/exporter"
+	"github.com/czerwonk/atlas_exporter/probe"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+const testResult = `{
+  "type": "ntp", "af": 4, "prb_id": 1, "msm_id": 1, "dst_addr": "192.0.2.123", "dst_name": "ntp.example.com",
+  "li": "no", "mode": "server", "ref-id": "GPS", "stratum": 1, "version": 4, "poll": 8, "precision": 0.5,
+  "root-delay": 0, "root-dispersion": 0.25,
+  "result": [
+    {"origin-ts": 1000, "receive-ts": 1000.25, "transmit-ts": 1000.5, "final-ts": 1000.0625, "rtt": 0.0625, "offset": 0.5},
+    {"x": "*"},
+    {"origin-ts": 1001, "receive-ts": 1001.5, "transmit-ts": 1001.5, "final-ts": 1001.125, "rtt": 0.125, "offset": -0.25}
+  ]
+}`
+
+func parseResult(t *testing.T, s string) *measurement.Result {
+	var r measurement.Result
+	err := json.Unmarshal([]byte(s), &r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &r
+}
+
+func TestExportPacketMetrics(t *testing.T) {
+	partition := exporter.NewHistogramPartition(config.HistogramPartition{})
+	m := exporter.NewMeasurement(&ntpExporter{"1"}, exporter.WithHistograms(
+		newRttHistogram("1", "4", nil, config.NativeHistogram{}, partition),
+		newOffsetHistogram("1", "4", nil, config.NativeHistogram{}, partition),
+	))
+	m.Add(parseResult(t, testResult), &probe.Probe{ID: 1, Asn4: 64496, CountryCode: "DE"})
+
+	expected := `
+# HELP atlas_ntp_offset_seconds Clock offset between probe and server
+# TYPE atlas_ntp_offset_seconds gauge
+atlas_ntp_offset_seconds{asn="64496",country_code="DE",dst_addr="192.0.2.123",dst_name="ntp.example.com",ip_version="4",lat="",long="",measurement="1",packet="0",probe="1"} 0.5
+atlas_ntp_offset_seconds{asn="64496",country_code="DE",dst_addr="192.0.2.123",dst_name="ntp.example.com",ip_version="4",lat="",long="",measurement="1",packet="2",probe="1"} -0.25
+# HELP atlas_ntp_rtt_seconds Round trip time between probe and server
+# TYPE atlas_ntp_rtt_seconds gauge
+atlas_ntp_rtt_seconds{asn="64496",country_code="DE",dst_addr="192.0.2.123",dst_name="ntp.example.com",ip_version="4",lat="",long="",measurement="1",packet="0",probe="1"} 0.0625
+atlas_ntp_rtt_seconds{asn="64496",country_code="DE",dst_addr="192.0.2.123",dst_name="ntp.example.com",ip_version="4",lat="",long="",measurement="1",packet="2",probe="1"} 0.125
+# HELP atlas_ntp_server_processing_seconds Time between the server receiving the request and sending the response (transmit - receive timestamp)
+# TYPE atlas_ntp_server_processing_seconds gauge
+atlas_ntp_server_processing_seconds{asn="64496",country_code="DE",dst_addr="192.0.2.123",dst_name="ntp.example.com",ip_version="4",lat="",long="",measurement="1",packet="0",probe="1"} 0.25
+atlas_ntp_server_processing_seconds{asn="64496",country_code="DE",dst_addr="192.0.2.123",dst_name="ntp.example.com",ip_version="4",lat="",long="",measurement="1",packet="2",probe="1"} 0
+# HELP atlas_ntp_success Server replied with a valid and synchronized response
+# TYPE atlas_ntp_success gauge
+atlas_ntp_success{asn="64496",country_code="DE",dst_addr="192.0.2.123",dst_name="ntp.example.com",ip_version="4",lat="",long="",measurement="1",probe="1"} 1
+# HELP atlas_ntp_rtt_seconds_hist Histogram of round trip times over all NTP packets
+# TYPE atlas_ntp_rtt_seconds_hist histogram
+atlas_ntp_rtt_seconds_hist_bucket{ip_version="4",measurement="1",le="0.005"} 0
+atlas_ntp_rtt_seconds_hist_bucket{ip_version="4",measurement="1",le="0.01"} 0
+atlas_ntp_rtt_seconds_hist_bucket{ip_version="4",measurement="1",le="0.025"} 0
+atlas_ntp_rtt_seconds_hist_bucket{ip_version="4",measurement="1",le="0.05"} 0
+atlas_ntp_rtt_seconds_hist_bucket{ip_version="4",measurement="1",le="0.1"} 1
+atlas_ntp_rtt_seconds_hist_bucket{ip_version="4",measurement="1",le="0.25"} 2
+atlas_ntp_rtt_seconds_hist_bucket{ip_version="4",measurement="1",le="+Inf"} 2
+atlas_ntp_rtt_seconds_hist_sum{ip_version="4",measurement="1"} 0.1875
+atlas_ntp_rtt_seconds_hist_count{ip_version="4",measurement="1"} 2
+# HELP atlas_ntp_offset_seconds_hist Histogram of clock offsets over all NTP packets
+# TYPE atlas_ntp_offset_seconds_hist histogram
+atlas_ntp_offset_seconds_hist_bucket{ip_version="4",measurement="1",le="-0.1"} 1
+atlas_ntp_offset_seconds_hist_bucket{ip_version="4",measurement="1",le="-0.01"} 1
+atlas_ntp_offset_seconds_hist_bucket{ip_version="4",measurement="1",le="-0.001"} 1
+atlas_ntp_offset_seconds_hist_bucket{ip_version="4",measurement="1",le="0.001"} 1
+atlas_ntp_offset_seconds_hist_bucket{ip_version="4",measurement="1",le="0.01"} 1
+atlas_ntp_offset_seconds_hist_bucket{ip_version="4",measurement="1",le="0.1"} 1
+atlas_ntp_offset_seconds_hist_bucket{ip_version="4",measurement="1",le="+Inf"} 2
+atlas_ntp_offset_seconds_hist_sum{ip_version="4",measurement="1"} 0.25
+atlas_ntp_offset_seconds_hist_count{ip_version="4",measurement="1"} 2
+`
+	err := testutil.CollectAndCompare(m, strings.NewReader(expected),
+		"atlas_ntp_offset_seconds", "atlas_ntp_rtt_seconds", "atlas_ntp_server_processing_seconds", "atlas_ntp_success",
+		"atlas_ntp_rtt_seconds_hist", "atlas_ntp_offset_seconds_hist")
+	assert.NoError(t, err)
+}
@@ -5,6 +5,7 @@ package ntp
 import (
 	"github.com/czerwonk/atlas_exporter/config"
 	"github.com/czerwonk/atlas_exporter/exporter"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 const (
@@ -12,12 +13,28 @@ const (
 	sub = "ntp"
 )
 
+func init() {
+	exporter.Register("ntp", NewMeasurement)
+}
+
 // NewMeasurement returns a new instance of `exorter.Measurement` for a NTP measurement
-func NewMeasurement(id string, cfg *config.Config) *exporter.Measurement {
-	opts := []exporter.MeasurementOpt{}
+func NewMeasurement(id, ipVersion string, cfg *config.Config) *exporter.Measurement {
+	buckets := cfg.HistogramBucketsForMeasurement(id)
+	partition := exporter.NewHistogramPartition(cfg.HistogramPartition)
+	opts := []exporter.MeasurementOpt{
+		exporter.WithHistograms(
+			newRttHistogram(id, ipVersion, buckets.NTP.Rtt, buckets.NTP.Native, partition),
+			newOffsetHistogram(id, ipVersion, buckets.NTP.Offset, buckets.NTP.Native, partition),
+		),
+	}
+
+	if m, _ := cfg.MeasurementByID(id); m.Summary.Enabled() {
+		opts = append(opts, exporter.WithHistograms(exporter.NewRttSummary(prometheus.BuildFQName(ns, sub, "rtt_seconds_summary"),
+			"Summary of round trip times over all NTP packets within a sliding window", id, ipVersion, m.Summary, partition, exporter.ResultValues(rtts))))
+	}
 
 	if cfg.FilterInvalidResults {
-		opts = append(opts, exporter.WithValidator(&exporter.DefaultResultValidator{}))
+		opts = append(opts, exporter.WithValidator(&ntpResultValidator{}))
 	}
 
 	return exporter.NewMeasurement(&ntpExporter{id}, opts...)
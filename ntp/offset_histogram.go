@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package ntp
+
+import (
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/czerwonk/atlas_exporter/config"
+	"github.com/czerwonk/atlas_exporter/exporter"
+	"github.com/czerwonk/atlas_exporter/probe"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type offsetHistogram struct {
+	offset    *prometheus.HistogramVec
+	partition *exporter.HistogramPartition
+}
+
+func newOffsetHistogram(id, ipVersion string, buckets []float64, native config.NativeHistogram, partition *exporter.HistogramPartition) exporter.Histogram {
+	if buckets == nil {
+		buckets = []float64{-0.1, -0.01, -0.001, 0.001, 0.01, 0.1}
+	}
+
+	return &offsetHistogram{
+		partition: partition,
+		offset: partition.NewHistogramVec(exporter.WithNativeHistogram(prometheus.HistogramOpts{
+			Namespace: ns,
+			Subsystem: sub,
+			Name:      "offset_seconds_hist",
+			Buckets:   buckets,
+			Help:      "Histogram of clock offsets over all NTP packets",
+			ConstLabels: prometheus.Labels{
+				"measurement": id,
+				"ip_version":  ipVersion,
+			},
+		}, native)),
+	}
+}
+
+func (h *offsetHistogram) ProcessResult(r *measurement.Result, probe *probe.Probe) {
+	hist := h.offset.WithLabelValues(h.partition.LabelValues(probe, r.Af())...)
+	for _, p := range r.NtpResults() {
+		if validReply(p) {
+			hist.Observe(p.Offset())
+		}
+	}
+}
+
+func (h *offsetHistogram) Hist() prometheus.Collector {
+	return h.offset
+}
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package ntp
+
+import (
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/DNS-OARC/ripeatlas/measurement/ntp"
+)
+
+const (
+	errorTimeout        = "timeout"
+	errorKissOfDeath    = "kiss_of_death"
+	errorUnsynchronized = "unsynchronized"
+	errorMalformed      = "malformed"
+
+	// stratumUnsynchronized is used by servers not synchronized to a reference clock (RFC 5905)
+	stratumUnsynchronized = 16
+	leapAlarm             = "unknown"
+)
+
+// validReply returns true if the packet is a reply with consistent timestamps
+func validReply(p *ntp.Result) bool {
+	return p.Rtt() > 0 &&
+		p.OriginTs() > 0 && p.ReceiveTs() > 0 && p.TransmitTs() > 0 && p.FinalTs() > 0 &&
+		p.TransmitTs() >= p.ReceiveTs() && p.FinalTs() >= p.OriginTs()
+}
+
+// malformedReply returns true if the packet is neither a valid reply nor a timeout (all values zero)
+func malformedReply(p *ntp.Result) bool {
+	if validReply(p) {
+		return false
+	}
+
+	return p.Rtt() != 0 || p.Offset() != 0 ||
+		p.OriginTs() != 0 || p.ReceiveTs() != 0 || p.TransmitTs() != 0 || p.FinalTs() != 0
+}
+
+// allRepliesMalformed returns true if the result has replies and none of them is a valid reply or timeout
+func allRepliesMalformed(r *measurement.Result) bool {
+	if len(r.NtpResults()) == 0 {
+		return false
+	}
+
+	for _, p := range r.NtpResults() {
+		if !malformedReply(p) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validReplies returns the valid replies of a result, skipping timeouts and malformed packets
+func validReplies(r *measurement.Result) []*ntp.Result {
+	replies := make([]*ntp.Result, 0, len(r.NtpResults()))
+	for _, p := range r.NtpResults() {
+		if validReply(p) {
+			replies = append(replies, p)
+		}
+	}
+
+	return replies
+}
+
+// errorReason returns why a query failed (empty if successful)
+func errorReason(r *measurement.Result) string {
+	if len(validReplies(r)) == 0 {
+		for _, p := range r.NtpResults() {
+			if malformedReply(p) {
+				return errorMalformed
+			}
+		}
+
+		return errorTimeout
+	}
+
+	if r.Stratum() == 0 {
+		return errorKissOfDeath
+	}
+
+	if r.Stratum() >= stratumUnsynchronized || r.Li() == leapAlarm {
+		return errorUnsynchronized
+	}
+
+	return ""
+}
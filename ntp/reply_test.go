@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package ntp
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/czerwonk/atlas_exporter/probe"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	validPacket     = `{"origin-ts": 1000, "receive-ts": 1000.25, "transmit-ts": 1000.5, "final-ts": 1000.0625, "rtt": 0.0625, "offset": 0.5}`
+	timeoutPacket   = `{"x": "*"}`
+	malformedPacket = `{"origin-ts": 1000, "receive-ts": 1000.5, "transmit-ts": 1000.25, "final-ts": 1000.0625, "rtt": 0.0625, "offset": 0.5}`
+)
+
+func ntpResult(stratum int, li string, packets ...string) string {
+	s := `{"type": "ntp", "af": 4, "prb_id": 1, "msm_id": 1, "dst_addr": "192.0.2.123", "li": "` + li + `", "stratum": ` + strconv.Itoa(stratum) + `, "result": [`
+	for i, p := range packets {
+		if i > 0 {
+			s += ","
+		}
+		s += p
+	}
+
+	return s + "]}"
+}
+
+func TestReplyClassification(t *testing.T) {
+	tests := []struct {
+		name      string
+		packet    string
+		valid     bool
+		malformed bool
+	}{
+		{
+			name:   "valid",
+			packet: validPacket,
+			valid:  true,
+		},
+		{
+			name:   "timeout",
+			packet: timeoutPacket,
+		},
+		{
+			name:      "transmit before receive",
+			packet:    malformedPacket,
+			malformed: true,
+		},
+		{
+			name:      "final before origin",
+			packet:    `{"origin-ts": 1000, "receive-ts": 1000.25, "transmit-ts": 1000.5, "final-ts": 999, "rtt": 0.0625, "offset": 0.5}`,
+			malformed: true,
+		},
+		{
+			name:      "missing timestamps",
+			packet:    `{"rtt": 0.0625, "offset": 0.5}`,
+			malformed: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := parseResult(t, ntpResult(1, "no", test.packet)).NtpResults()[0]
+			assert.Equal(t, test.valid, validReply(p), "valid")
+			assert.Equal(t, test.malformed, malformedReply(p), "malformed")
+		})
+	}
+}
+
+func TestValidRepliesSkipsMalformed(t *testing.T) {
+	r := parseResult(t, ntpResult(1, "no", validPacket, malformedPacket, timeoutPacket, validPacket))
+	assert.Len(t, validReplies(r), 2)
+}
+
+func TestErrorReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   string
+		expected string
+	}{
+		{
+			name:   "success",
+			result: ntpResult(1, "no", validPacket, timeoutPacket),
+		},
+		{
+			name:   "success with malformed packet",
+			result: ntpResult(1, "no", validPacket, malformedPacket),
+		},
+		{
+			name:     "timeout",
+			result:   ntpResult(0, "", timeoutPacket, timeoutPacket, timeoutPacket),
+			expected: errorTimeout,
+		},
+		{
+			name:     "malformed",
+			result:   ntpResult(1, "no", malformedPacket, timeoutPacket),
+			expected: errorMalformed,
+		},
+		{
+			name:     "kiss of death",
+			result:   ntpResult(0, "no", validPacket),
+			expected: errorKissOfDeath,
+		},
+		{
+			name:     "unsynchronized stratum",
+			result:   ntpResult(stratumUnsynchronized, "no", validPacket),
+			expected: errorUnsynchronized,
+		},
+		{
+			name:     "leap alarm",
+			result:   ntpResult(2, leapAlarm, validPacket),
+			expected: errorUnsynchronized,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, errorReason(parseResult(t, test.result)))
+		})
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   string
+		probe    *probe.Probe
+		expected bool
+	}{
+		{
+			name:     "valid",
+			result:   ntpResult(1, "no", validPacket),
+			probe:    &probe.Probe{ID: 1, Asn4: 64496},
+			expected: true,
+		},
+		{
+			name:     "single malformed reply",
+			result:   ntpResult(1, "no", validPacket, malformedPacket),
+			probe:    &probe.Probe{ID: 1, Asn4: 64496},
+			expected: true,
+		},
+		{
+			name:     "malformed reply and timeout",
+			result:   ntpResult(1, "no", malformedPacket, timeoutPacket),
+			probe:    &probe.Probe{ID: 1, Asn4: 64496},
+			expected: true,
+		},
+		{
+			name:   "all replies malformed",
+			result: ntpResult(1, "no", malformedPacket, malformedPacket),
+			probe:  &probe.Probe{ID: 1, Asn4: 64496},
+		},
+		{
+			name:   "probe without ASN for address family",
+			result: ntpResult(1, "no", validPacket),
+			probe:  &probe.Probe{ID: 1, Asn6: 64496},
+		},
+	}
+
+	v := &ntpResultValidator{}
+	for _, test := range tests {
+		t.Run(test.name, func(te *testing.T) {
+			assert.Equal(te, test.expected, v.IsValid(parseResult(te, test.result), test.probe))
+		})
+	}
+}
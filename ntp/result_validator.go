@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package ntp
+
+import (
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/czerwonk/atlas_exporter/probe"
+)
+
+type ntpResultValidator struct {
+}
+
+// IsValid returns whether an result is valid or not (e.g. IPv6 measurement and Probe does not support IPv6 or all
+// replies are malformed). Results with single malformed replies are kept, these replies are skipped on export.
+func (m *ntpResultValidator) IsValid(res *measurement.Result, probe *probe.Probe) bool {
+	if probe.ASNForIPVersion(res.Af()) == 0 {
+		return false
+	}
+
+	return !allRepliesMalformed(res)
+}
@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package ntp
+
+import (
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/czerwonk/atlas_exporter/config"
+	"github.com/czerwonk/atlas_exporter/exporter"
+	"github.com/czerwonk/atlas_exporter/probe"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type rttHistogram struct {
+	rtt       *prometheus.HistogramVec
+	partition *exporter.HistogramPartition
+}
+
+func newRttHistogram(id, ipVersion string, buckets []float64, native config.NativeHistogram, partition *exporter.HistogramPartition) exporter.Histogram {
+	if buckets == nil {
+		buckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25}
+	}
+
+	return &rttHistogram{
+		partition: partition,
+		rtt: partition.NewHistogramVec(exporter.WithNativeHistogram(prometheus.HistogramOpts{
+			Namespace: ns,
+			Subsystem: sub,
+			Name:      "rtt_seconds_hist",
+			Buckets:   buckets,
+			Help:      "Histogram of round trip times over all NTP packets",
+			ConstLabels: prometheus.Labels{
+				"measurement": id,
+				"ip_version":  ipVersion,
+			},
+		}, native)),
+	}
+}
+
+func (h *rttHistogram) ProcessResult(r *measurement.Result, probe *probe.Probe) {
+	hist := h.rtt.WithLabelValues(h.partition.LabelValues(probe, r.Af())...)
+	for _, rtt := range rtts(r) {
+		hist.Observe(rtt)
+	}
+}
+
+func (h *rttHistogram) Hist() prometheus.Collector {
+	return h.rtt
+}
+
+// rtts returns the round trip times of all valid NTP replies of a result
+func rtts(r *measurement.Result) []float64 {
+	res := make([]float64, 0, len(r.NtpResults()))
+	for _, p := range r.NtpResults() {
+		if validReply(p) {
+			res = append(res, p.Rtt())
+		}
+	}
+
+	return res
+}
@@ -5,6 +5,7 @@ package ping
 import (
 	"github.com/czerwonk/atlas_exporter/config"
 	"github.com/czerwonk/atlas_exporter/exporter"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 const (
@@ -12,10 +13,21 @@ const (
 	sub = "ping"
 )
 
+func init() {
+	exporter.Register("ping", NewMeasurement)
+}
+
 // NewMeasurement returns a new instance of `exorter.Measurement` for a ping measurement
 func NewMeasurement(id, ipVersion string, cfg *config.Config) *exporter.Measurement {
+	buckets := cfg.HistogramBucketsForMeasurement(id)
+	partition := exporter.NewHistogramPartition(cfg.HistogramPartition)
 	opts := []exporter.MeasurementOpt{
-		exporter.WithHistograms(newRttHistogram(id, ipVersion, cfg.HistogramBuckets.Ping.Rtt)),
+		exporter.WithHistograms(newRttHistogram(id, ipVersion, buckets.Ping.Rtt, buckets.Ping.Native, partition)),
+	}
+
+	if m, _ := cfg.MeasurementByID(id); m.Summary.Enabled() {
+		opts = append(opts, exporter.WithHistograms(exporter.NewRttSummary(prometheus.BuildFQName(ns, sub, "rtt_summary"),
+			"Summary of round trip times over all ICMP requests within a sliding window", id, ipVersion, m.Summary, partition, exporter.ResultValues(rtts))))
 	}
 
 	if cfg.FilterInvalidResults {
@@ -4,21 +4,25 @@ package ping
 
 import (
 	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/czerwonk/atlas_exporter/config"
 	"github.com/czerwonk/atlas_exporter/exporter"
+	"github.com/czerwonk/atlas_exporter/probe"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 type rttHistogram struct {
-	rtt prometheus.Histogram
+	rtt       *prometheus.HistogramVec
+	partition *exporter.HistogramPartition
 }
 
-func newRttHistogram(id, ipVersion string, buckets []float64) exporter.Histogram {
+func newRttHistogram(id, ipVersion string, buckets []float64, native config.NativeHistogram, partition *exporter.HistogramPartition) exporter.Histogram {
 	if buckets == nil {
 		buckets = []float64{10, 20, 50, 100}
 	}
 
 	return &rttHistogram{
-		rtt: prometheus.NewHistogram(prometheus.HistogramOpts{
+		partition: partition,
+		rtt: partition.NewHistogramVec(exporter.WithNativeHistogram(prometheus.HistogramOpts{
 			Namespace: ns,
 			Subsystem: sub,
 			Name:      "rtt_hist",
@@ -28,18 +32,29 @@ func newRttHistogram(id, ipVersion string, buckets []float64) exporter.Histogram
 				"measurement": id,
 				"ip_version":  ipVersion,
 			},
-		}),
+		}, native)),
 	}
 }
 
-func (h *rttHistogram) ProcessResult(r *measurement.Result) {
+func (h *rttHistogram) ProcessResult(r *measurement.Result, probe *probe.Probe) {
+	hist := h.rtt.WithLabelValues(h.partition.LabelValues(probe, r.Af())...)
+	for _, rtt := range rtts(r) {
+		hist.Observe(rtt)
+	}
+}
+
+func (h *rttHistogram) Hist() prometheus.Collector {
+	return h.rtt
+}
+
+// rtts returns the round trip times of all ICMP requests of a result
+func rtts(r *measurement.Result) []float64 {
+	res := make([]float64, 0, len(r.PingResults()))
 	for _, p := range r.PingResults() {
 		if p.Rtt() > 0 {
-			h.rtt.Observe(p.Rtt())
+			res = append(res, p.Rtt())
 		}
 	}
-}
 
-func (h *rttHistogram) Hist() prometheus.Histogram {
-	return h.rtt
+	return res
 }
@@ -18,6 +18,13 @@ type Probe struct {
 	Geometry    struct {
 		Coordinates []float64 `json:"coordinates"`
 	} `json:"geometry"`
+	Tags []Tag `json:"tags"`
+}
+
+// Tag is a tag assigned to a probe (by the user or automatically by Atlas)
+type Tag struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
 }
 
 // FromJSON parses json and returns a probe
@@ -58,3 +65,14 @@ func (p *Probe) Latitude() string {
 
 	return strconv.FormatFloat(p.Geometry.Coordinates[1], 'f', 4, 64)
 }
+
+// HasTag returns true if a tag with the given slug is assigned to the probe
+func (p *Probe) HasTag(slug string) bool {
+	for _, t := range p.Tags {
+		if t.Slug == slug {
+			return true
+		}
+	}
+
+	return false
+}
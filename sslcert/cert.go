@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package sslcert
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// parseChain parses the PEM encoded certificates of a result (leaf certificate first)
+func parseChain(pems []string) []*x509.Certificate {
+	chain := make([]*x509.Certificate, 0, len(pems))
+
+	for _, p := range pems {
+		block, _ := pem.Decode([]byte(p))
+		if block == nil {
+			log.Debugf("could not decode PEM block")
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			log.Debugf("could not parse certificate: %v", err)
+			continue
+		}
+
+		chain = append(chain, cert)
+	}
+
+	return chain
+}
+
+// chainNotAfter returns the end of the validity period of the certificate expiring first in the chain
+func chainNotAfter(chain []*x509.Certificate) time.Time {
+	var notAfter time.Time
+	for i, c := range chain {
+		if i == 0 || c.NotAfter.Before(notAfter) {
+			notAfter = c.NotAfter
+		}
+	}
+
+	return notAfter
+}
+
+// sanCount returns the number of subject alternative names of a certificate
+func sanCount(cert *x509.Certificate) int {
+	return len(cert.DNSNames) + len(cert.IPAddresses) + len(cert.EmailAddresses) + len(cert.URIs)
+}
+
+// keySize returns the size of the public key in bits (0 if unknown)
+func keySize(cert *x509.Certificate) int {
+	switch k := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return k.N.BitLen()
+	case *ecdsa.PublicKey:
+		return k.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return len(k) * 8
+	}
+
+	return 0
+}
@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package sslcert
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseChain(t *testing.T) {
+	leaf := selfSignedPEM(t, newECDSAKey(t, elliptic.P256()))
+	intermediate := selfSignedPEM(t, newECDSAKey(t, elliptic.P384()))
+
+	tests := []struct {
+		name     string
+		pems     []string
+		expected int
+	}{
+		{
+			name:     "valid chain",
+			pems:     []string{leaf, intermediate},
+			expected: 2,
+		},
+		{
+			name:     "invalid PEM is skipped",
+			pems:     []string{leaf, "not a certificate", intermediate},
+			expected: 2,
+		},
+		{
+			name:     "invalid certificate is skipped",
+			pems:     []string{string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("garbage")})), leaf},
+			expected: 1,
+		},
+		{
+			name: "empty",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(te *testing.T) {
+			assert.Len(te, parseChain(test.pems), test.expected)
+		})
+	}
+}
+
+func TestParseChainKeepsOrder(t *testing.T) {
+	leaf := selfSignedPEM(t, newECDSAKey(t, elliptic.P256()))
+	intermediate := selfSignedPEM(t, newECDSAKey(t, elliptic.P384()))
+
+	chain := parseChain([]string{leaf, "", intermediate})
+	if assert.Len(t, chain, 2) {
+		assert.Equal(t, 256, keySize(chain[0]))
+		assert.Equal(t, 384, keySize(chain[1]))
+	}
+}
+
+func TestKeySize(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		key      crypto.Signer
+		expected int
+	}{
+		{
+			name:     "RSA",
+			key:      rsaKey,
+			expected: 2048,
+		},
+		{
+			name:     "ECDSA P-256",
+			key:      newECDSAKey(t, elliptic.P256()),
+			expected: 256,
+		},
+		{
+			name:     "ECDSA P-521",
+			key:      newECDSAKey(t, elliptic.P521()),
+			expected: 521,
+		},
+		{
+			name:     "Ed25519",
+			key:      edKey,
+			expected: 256,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(te *testing.T) {
+			chain := parseChain([]string{selfSignedPEM(te, test.key)})
+			if assert.Len(te, chain, 1) {
+				assert.Equal(te, test.expected, keySize(chain[0]))
+			}
+		})
+	}
+}
+
+func TestKeySizeUnknown(t *testing.T) {
+	assert.Equal(t, 0, keySize(&x509.Certificate{}))
+}
+
+func newECDSAKey(t *testing.T, curve elliptic.Curve) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func selfSignedPEM(t *testing.T, key crypto.Signer) string {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "www.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	b, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: b}))
+}
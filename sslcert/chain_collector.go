@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package sslcert
+
+import (
+	"crypto/x509"
+	"sync"
+	"time"
+
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/czerwonk/atlas_exporter/probe"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// probeChain is the parsed and verified certificate chain of a result
+type probeChain struct {
+	result *measurement.Result
+	certs  []*x509.Certificate
+	// verified is true if the chain was verified (verifier available and certificates present)
+	verified bool
+	valid    bool
+	reason   string
+}
+
+// validity returns whether the chain is valid at the given time. A chain verified as valid becomes expired when a
+// certificate of the chain expires, so verification does not have to be repeated on each scrape.
+func (c *probeChain) validity(now time.Time) (valid bool, reason string) {
+	if c.valid && now.After(chainNotAfter(c.certs)) {
+		return false, reasonExpired
+	}
+
+	return c.valid, c.reason
+}
+
+// chainCollector parses and verifies the certificate chain of the latest result of each probe when the result is
+// added. The exporter reads the stored chains on scrape, the collector does not export metrics itself.
+type chainCollector struct {
+	verifier *chainVerifier
+	chains   map[int]*probeChain
+	mu       sync.RWMutex
+}
+
+func newChainCollector(verifier *chainVerifier) *chainCollector {
+	return &chainCollector{
+		verifier: verifier,
+		chains:   make(map[int]*probeChain),
+	}
+}
+
+func (c *chainCollector) ProcessResult(r *measurement.Result, p *probe.Probe) {
+	chain := c.newProbeChain(r)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.chains[r.PrbId()] = chain
+}
+
+// chain returns the chain of a result (parsed and verified if the result was not processed before)
+func (c *chainCollector) chain(r *measurement.Result) *probeChain {
+	c.mu.RLock()
+	chain, found := c.chains[r.PrbId()]
+	c.mu.RUnlock()
+
+	if found && chain.result == r {
+		return chain
+	}
+
+	return c.newProbeChain(r)
+}
+
+func (c *chainCollector) newProbeChain(r *measurement.Result) *probeChain {
+	chain := &probeChain{
+		result: r,
+		certs:  parseChain(r.Cert()),
+	}
+
+	if c.verifier != nil && len(chain.certs) > 0 {
+		chain.verified = true
+		chain.valid, chain.reason = c.verifier.verify(chain.certs, r.DstName())
+	}
+
+	return chain
+}
+
+func (c *chainCollector) Describe(ch chan<- *prometheus.Desc) {
+}
+
+func (c *chainCollector) Collect(ch chan<- prometheus.Metric) {
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package sslcert
+
+import (
+	"crypto/elliptic"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/czerwonk/atlas_exporter/probe"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChainCollector(t *testing.T) {
+	ca, caKey := newTestCert(t, nil, nil, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(2 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	})
+	leaf, _ := newTestCert(t, ca, caKey, &x509.Certificate{
+		Subject:   pkix.Name{CommonName: "www.example.com"},
+		DNSNames:  []string{"www.example.com"},
+		NotBefore: time.Now().Add(-time.Hour),
+		NotAfter:  time.Now().Add(time.Hour),
+	})
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw}), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := newChainVerifier(caFile, "www.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := newChainCollector(v)
+	r := sslCertResult(t, 1, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Raw})))
+	c.ProcessResult(r, &probe.Probe{ID: 1})
+
+	chain := c.chain(r)
+	assert.Same(t, chain, c.chain(r), "chain is verified once when the result is added")
+	assert.True(t, chain.verified)
+	assert.Len(t, chain.certs, 1)
+
+	valid, reason := chain.validity(time.Now())
+	assert.True(t, valid)
+	assert.Equal(t, reasonNone, reason)
+
+	valid, reason = chain.validity(time.Now().Add(90 * time.Minute))
+	assert.False(t, valid, "chain expires after verification")
+	assert.Equal(t, reasonExpired, reason)
+
+	other := sslCertResult(t, 2)
+	assert.False(t, c.chain(other).verified, "chain without certificates is not verified")
+}
+
+func TestChainCollectorWithoutVerifier(t *testing.T) {
+	c := newChainCollector(nil)
+	r := sslCertResult(t, 1, selfSignedPEM(t, newECDSAKey(t, elliptic.P256())))
+	c.ProcessResult(r, &probe.Probe{ID: 1})
+
+	chain := c.chain(r)
+	assert.Len(t, chain.certs, 1)
+	assert.False(t, chain.verified)
+}
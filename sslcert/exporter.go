@@ -3,10 +3,9 @@
 package sslcert
 
 import (
-	"crypto/sha256"
-	"encoding/pem"
-	"fmt"
+	"crypto/x509"
 	"strconv"
+	"time"
 
 	"github.com/DNS-OARC/ripeatlas/measurement"
 	"github.com/czerwonk/atlas_exporter/probe"
@@ -20,7 +19,16 @@ var (
 	successDesc          *prometheus.Desc
 	alertLevelDesc       *prometheus.Desc
 	alertDescriptionDesc *prometheus.Desc
-	certFingerprint      string
+	notBeforeDesc        *prometheus.Desc
+	notAfterDesc         *prometheus.Desc
+	expiryDaysDesc       *prometheus.Desc
+	chainNotAfterDesc    *prometheus.Desc
+	chainLengthDesc      *prometheus.Desc
+	sanCountDesc         *prometheus.Desc
+	keySizeDesc          *prometheus.Desc
+	certInfoDesc         *prometheus.Desc
+	certMismatchDesc     *prometheus.Desc
+	certValidDesc        *prometheus.Desc
 )
 
 func init() {
@@ -31,20 +39,27 @@ func init() {
 	rttDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "rtt"), "Round trip time in ms", labels, nil)
 	alertLevelDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "alert_level"), "Status of the SSL/TLS certificate (0 = valid)", labels, nil)
 	alertDescriptionDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "alert_description"), "Description for the alert level (see RIPE Atlas documentation)", labels, nil)
+	notBeforeDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "not_before_timestamp_seconds"), "Start of the validity period of the leaf certificate", labels, nil)
+	notAfterDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "not_after_timestamp_seconds"), "End of the validity period of the leaf certificate", labels, nil)
+	expiryDaysDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "expiry_days"), "Days until the leaf certificate expires", labels, nil)
+	chainNotAfterDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "chain_not_after_timestamp_seconds"), "End of the validity period of the certificate expiring first in the chain", labels, nil)
+	chainLengthDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "chain_length"), "Number of certificates in the chain sent by the server", labels, nil)
+	sanCountDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "san_count"), "Number of subject alternative names of the leaf certificate", labels, nil)
+	keySizeDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "key_size_bits"), "Size of the public key of the leaf certificate", labels, nil)
+	certValidDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "cert_valid"), "Certificate chain is valid for the trust store and hostname (reason if not)", append(labels, "reason"), nil)
+	certMismatchDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "cert_mismatch"), "Leaf certificate differs from the one seen by the majority of probes", labels, nil)
+	certInfoDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "cert_info"), "Subject, issuer and algorithms of the leaf certificate", append(labels, "subject_cn", "issuer_cn", "key_algorithm", "signature_algorithm"), nil)
 }
 
 type sslCertExporter struct {
-	id string
+	id           string
+	fingerprints *fingerprintCollector
+	chains       *chainCollector
 }
 
 // Export exports a prometheus metric
 func (m *sslCertExporter) Export(res *measurement.Result, probe *probe.Probe, ch chan<- prometheus.Metric) {
-	if len(res.Cert()) > 0 {
-		block, _ := pem.Decode([]byte(res.Cert()[0]))
-		if block != nil {
-			certFingerprint = fmt.Sprintf("%x", sha256.Sum256(block.Bytes))
-		}
-	}
+	certFingerprint := fingerprint(res)
 
 	labelValues := []string{
 		m.id,
@@ -58,6 +73,19 @@ func (m *sslCertExporter) Export(res *measurement.Result, probe *probe.Probe, ch
 		certFingerprint,
 	}
 
+	chain := m.chains.chain(res)
+	m.exportChain(chain.certs, labelValues, ch)
+
+	if chain.verified {
+		valid, reason := chain.validity(time.Now())
+		ch <- prometheus.MustNewConstMetric(certValidDesc, prometheus.GaugeValue, boolToFloat(valid), append(labelValues, reason)...)
+	}
+
+	if len(certFingerprint) > 0 {
+		mismatch := certFingerprint != m.fingerprints.majority()
+		ch <- prometheus.MustNewConstMetric(certMismatchDesc, prometheus.GaugeValue, boolToFloat(mismatch), labelValues...)
+	}
+
 	ver, _ := strconv.ParseFloat(res.Ver(), 64)
 	ch <- prometheus.MustNewConstMetric(sslVerDesc, prometheus.GaugeValue, ver, labelValues...)
 
@@ -77,6 +105,26 @@ func (m *sslCertExporter) Export(res *measurement.Result, probe *probe.Probe, ch
 	}
 }
 
+func (m *sslCertExporter) exportChain(chain []*x509.Certificate, labelValues []string, ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(chainLengthDesc, prometheus.GaugeValue, float64(len(chain)), labelValues...)
+	if len(chain) == 0 {
+		return
+	}
+
+	leaf := chain[0]
+	ch <- prometheus.MustNewConstMetric(notBeforeDesc, prometheus.GaugeValue, float64(leaf.NotBefore.Unix()), labelValues...)
+	ch <- prometheus.MustNewConstMetric(notAfterDesc, prometheus.GaugeValue, float64(leaf.NotAfter.Unix()), labelValues...)
+	ch <- prometheus.MustNewConstMetric(expiryDaysDesc, prometheus.GaugeValue, time.Until(leaf.NotAfter).Hours()/24, labelValues...)
+	ch <- prometheus.MustNewConstMetric(sanCountDesc, prometheus.GaugeValue, float64(sanCount(leaf)), labelValues...)
+	ch <- prometheus.MustNewConstMetric(keySizeDesc, prometheus.GaugeValue, float64(keySize(leaf)), labelValues...)
+	ch <- prometheus.MustNewConstMetric(certInfoDesc, prometheus.GaugeValue, 1, append(labelValues,
+		leaf.Subject.CommonName,
+		leaf.Issuer.CommonName,
+		leaf.PublicKeyAlgorithm.String(),
+		leaf.SignatureAlgorithm.String())...)
+	ch <- prometheus.MustNewConstMetric(chainNotAfterDesc, prometheus.GaugeValue, float64(chainNotAfter(chain).Unix()), labelValues...)
+}
+
 // Describe exports metric descriptions for Prometheus
 func (m *sslCertExporter) Describe(ch chan<- *prometheus.Desc) {
 	ch <- successDesc
@@ -84,4 +132,22 @@ func (m *sslCertExporter) Describe(ch chan<- *prometheus.Desc) {
 	ch <- sslVerDesc
 	ch <- alertLevelDesc
 	ch <- alertDescriptionDesc
+	ch <- notBeforeDesc
+	ch <- notAfterDesc
+	ch <- expiryDaysDesc
+	ch <- chainNotAfterDesc
+	ch <- chainLengthDesc
+	ch <- sanCountDesc
+	ch <- keySizeDesc
+	ch <- certInfoDesc
+	ch <- certMismatchDesc
+	ch <- certValidDesc
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
 }
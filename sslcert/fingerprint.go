@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package sslcert
+
+import (
+	"crypto/sha256"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/czerwonk/atlas_exporter/probe"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// fingerprint returns the SHA256 fingerprint of the leaf certificate of a result (empty if not present)
+func fingerprint(res *measurement.Result) string {
+	if len(res.Cert()) == 0 {
+		return ""
+	}
+
+	block, _ := pem.Decode([]byte(res.Cert()[0]))
+	if block == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(block.Bytes))
+}
+
+// fingerprintCollector tracks the leaf certificate fingerprints seen by the probes of a measurement. The majority is
+// updated when a result is processed, so it is not recomputed for each probe on scrape.
+type fingerprintCollector struct {
+	fingerprints map[int]string
+	counts       map[string]int
+	majorityFp   string
+	distinctDesc *prometheus.Desc
+	mu           sync.RWMutex
+}
+
+func newFingerprintCollector(id, ipVersion string) *fingerprintCollector {
+	return &fingerprintCollector{
+		fingerprints: make(map[int]string),
+		counts:       make(map[string]int),
+		distinctDesc: prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "distinct_fingerprints"), "Number of distinct leaf certificates seen by the probes", nil, prometheus.Labels{
+			"measurement": id,
+			"ip_version":  ipVersion,
+		}),
+	}
+}
+
+func (c *fingerprintCollector) ProcessResult(r *measurement.Result, p *probe.Probe) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if old, found := c.fingerprints[r.PrbId()]; found {
+		c.counts[old]--
+		if c.counts[old] == 0 {
+			delete(c.counts, old)
+		}
+	}
+
+	fp := fingerprint(r)
+	if len(fp) == 0 {
+		delete(c.fingerprints, r.PrbId())
+	} else {
+		c.fingerprints[r.PrbId()] = fp
+		c.counts[fp]++
+	}
+
+	c.majorityFp = c.computeMajority()
+}
+
+// computeMajority returns the fingerprint seen by most of the probes (empty if no certificate was seen).
+// Ties are broken by choosing the lexicographically smallest fingerprint.
+func (c *fingerprintCollector) computeMajority() string {
+	fps := make([]string, 0, len(c.counts))
+	for fp := range c.counts {
+		fps = append(fps, fp)
+	}
+	sort.Strings(fps)
+
+	var majority string
+	for _, fp := range fps {
+		if c.counts[fp] > c.counts[majority] {
+			majority = fp
+		}
+	}
+
+	return majority
+}
+
+// majority returns the fingerprint seen by most of the probes (empty if no certificate was seen)
+func (c *fingerprintCollector) majority() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.majorityFp
+}
+
+func (c *fingerprintCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.distinctDesc
+}
+
+func (c *fingerprintCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ch <- prometheus.MustNewConstMetric(c.distinctDesc, prometheus.GaugeValue, float64(len(c.counts)))
+}
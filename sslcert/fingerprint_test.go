@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package sslcert
+
+import (
+	"crypto/elliptic"
+	"encoding/json"
+	"testing"
+
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/czerwonk/atlas_exporter/probe"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMajority(t *testing.T) {
+	certA := sslCertResult(t, 0, selfSignedPEM(t, newECDSAKey(t, elliptic.P256())))
+	certB := sslCertResult(t, 0, selfSignedPEM(t, newECDSAKey(t, elliptic.P256())))
+	fpA, fpB := fingerprint(certA), fingerprint(certB)
+	smallest := min(fpA, fpB)
+
+	tests := []struct {
+		name     string
+		probes   map[int]*measurement.Result
+		expected string
+	}{
+		{
+			name: "no certificates",
+		},
+		{
+			name:     "single probe",
+			probes:   map[int]*measurement.Result{1: certA},
+			expected: fpA,
+		},
+		{
+			name:     "mismatch",
+			probes:   map[int]*measurement.Result{1: certB, 2: certA, 3: certB},
+			expected: fpB,
+		},
+		{
+			name:     "tie",
+			probes:   map[int]*measurement.Result{1: certA, 2: certB},
+			expected: smallest,
+		},
+		{
+			name:     "tie with more probes",
+			probes:   map[int]*measurement.Result{1: certA, 2: certB, 3: certB, 4: certA},
+			expected: smallest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(te *testing.T) {
+			c := newFingerprintCollector("1", "4")
+			for id, r := range test.probes {
+				c.ProcessResult(sslCertResult(te, id, r.Cert()...), &probe.Probe{ID: id})
+			}
+
+			for i := 0; i < 10; i++ {
+				assert.Equal(te, test.expected, c.majority())
+			}
+		})
+	}
+}
+
+func TestMajorityProbeWithoutCertificate(t *testing.T) {
+	cert := sslCertResult(t, 1, selfSignedPEM(t, newECDSAKey(t, elliptic.P256())))
+
+	c := newFingerprintCollector("1", "4")
+	c.ProcessResult(cert, &probe.Probe{ID: 1})
+	assert.Equal(t, fingerprint(cert), c.majority())
+
+	c.ProcessResult(sslCertResult(t, 1), &probe.Probe{ID: 1})
+	assert.Empty(t, c.majority())
+}
+
+func sslCertResult(t *testing.T, prbID int, pems ...string) *measurement.Result {
+	b, err := json.Marshal(map[string]interface{}{
+		"type":   "sslcert",
+		"prb_id": prbID,
+		"cert":   pems,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var r measurement.Result
+	err = json.Unmarshal(b, &r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &r
+}
+
+func TestMajorityFollowsCertificateChanges(t *testing.T) {
+	certA := selfSignedPEM(t, newECDSAKey(t, elliptic.P256()))
+	certB := selfSignedPEM(t, newECDSAKey(t, elliptic.P256()))
+	fpA := fingerprint(sslCertResult(t, 0, certA))
+	fpB := fingerprint(sslCertResult(t, 0, certB))
+
+	c := newFingerprintCollector("1", "4")
+	c.ProcessResult(sslCertResult(t, 1, certA), &probe.Probe{ID: 1})
+	c.ProcessResult(sslCertResult(t, 2, certA), &probe.Probe{ID: 2})
+	c.ProcessResult(sslCertResult(t, 3, certB), &probe.Probe{ID: 3})
+	assert.Equal(t, fpA, c.majority())
+	assert.Len(t, c.counts, 2)
+
+	c.ProcessResult(sslCertResult(t, 1, certB), &probe.Probe{ID: 1})
+	assert.Equal(t, fpB, c.majority(), "probe switched to the other certificate")
+
+	c.ProcessResult(sslCertResult(t, 2, certB), &probe.Probe{ID: 2})
+	assert.Equal(t, fpB, c.majority())
+	assert.Len(t, c.counts, 1, "certificate not seen anymore is removed")
+}
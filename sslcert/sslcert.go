@@ -5,6 +5,7 @@ package sslcert
 import (
 	"github.com/czerwonk/atlas_exporter/config"
 	"github.com/czerwonk/atlas_exporter/exporter"
+	log "github.com/sirupsen/logrus"
 )
 
 const (
@@ -12,13 +13,28 @@ const (
 	sub = "sslcert"
 )
 
+func init() {
+	exporter.Register("sslcert", NewMeasurement)
+}
+
 // NewMeasurement returns a new instance of `exorter.Measurement` for a SSL measurement
-func NewMeasurement(id string, cfg *config.Config) *exporter.Measurement {
-	opts := []exporter.MeasurementOpt{}
+func NewMeasurement(id, ipVersion string, cfg *config.Config) *exporter.Measurement {
+	m, _ := cfg.MeasurementByID(id)
+	verifier, err := newChainVerifier(m.SSLCert.CAFile, m.SSLCert.Hostname)
+	if err != nil {
+		log.Errorf("certificates of measurement %s can not be verified: %v", id, err)
+	}
+
+	fingerprints := newFingerprintCollector(id, ipVersion)
+	chains := newChainCollector(verifier)
+
+	opts := []exporter.MeasurementOpt{
+		exporter.WithCollectors(fingerprints, chains),
+	}
 
 	if cfg.FilterInvalidResults {
 		opts = append(opts, exporter.WithValidator(&exporter.DefaultResultValidator{}))
 	}
 
-	return exporter.NewMeasurement(&sslCertExporter{id}, opts...)
+	return exporter.NewMeasurement(&sslCertExporter{id: id, fingerprints: fingerprints, chains: chains}, opts...)
 }
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package sslcert
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	reasonNone             = "none"
+	reasonExpired          = "expired"
+	reasonUnknownAuthority = "unknown_authority"
+	reasonHostnameMismatch = "hostname_mismatch"
+	reasonInvalid          = "invalid"
+)
+
+// chainVerifier verifies certificate chains against a trust store and hostname
+type chainVerifier struct {
+	roots    *x509.CertPool
+	hostname string
+}
+
+func newChainVerifier(caFile, hostname string) (*chainVerifier, error) {
+	roots, err := loadRoots(caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chainVerifier{roots: roots, hostname: hostname}, nil
+}
+
+func loadRoots(caFile string) (*x509.CertPool, error) {
+	if len(caFile) == 0 {
+		return x509.SystemCertPool()
+	}
+
+	b, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read CA file: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("no certificates found in CA file %s", caFile)
+	}
+
+	return pool, nil
+}
+
+// verify verifies a chain (leaf certificate first) and returns the reason if the chain is not valid
+func (v *chainVerifier) verify(chain []*x509.Certificate, dstName string) (valid bool, reason string) {
+	intermediates := x509.NewCertPool()
+	for _, c := range chain[1:] {
+		intermediates.AddCert(c)
+	}
+
+	hostname := v.hostname
+	if len(hostname) == 0 {
+		hostname = dstName
+	}
+
+	_, err := chain[0].Verify(x509.VerifyOptions{
+		Roots:         v.roots,
+		Intermediates: intermediates,
+		DNSName:       hostname,
+		CurrentTime:   time.Now(),
+	})
+	if err != nil {
+		return false, verifyErrorReason(err)
+	}
+
+	return true, reasonNone
+}
+
+func verifyErrorReason(err error) string {
+	var invalidErr x509.CertificateInvalidError
+	if errors.As(err, &invalidErr) && invalidErr.Reason == x509.Expired {
+		return reasonExpired
+	}
+
+	var authorityErr x509.UnknownAuthorityError
+	if errors.As(err, &authorityErr) {
+		return reasonUnknownAuthority
+	}
+
+	var hostnameErr x509.HostnameError
+	if errors.As(err, &hostnameErr) {
+		return reasonHostnameMismatch
+	}
+
+	return reasonInvalid
+}
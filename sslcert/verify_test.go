@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package sslcert
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	ca, caKey := newTestCert(t, nil, nil, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	})
+	leaf, _ := newTestCert(t, ca, caKey, &x509.Certificate{
+		Subject:   pkix.Name{CommonName: "www.example.com"},
+		DNSNames:  []string{"www.example.com"},
+		NotBefore: time.Now().Add(-time.Hour),
+		NotAfter:  time.Now().Add(time.Hour),
+	})
+	selfSigned, _ := newTestCert(t, nil, nil, &x509.Certificate{
+		Subject:   pkix.Name{CommonName: "www.example.com"},
+		DNSNames:  []string{"www.example.com"},
+		NotBefore: time.Now().Add(-time.Hour),
+		NotAfter:  time.Now().Add(time.Hour),
+	})
+	expired, _ := newTestCert(t, ca, caKey, &x509.Certificate{
+		Subject:   pkix.Name{CommonName: "www.example.com"},
+		DNSNames:  []string{"www.example.com"},
+		NotBefore: time.Now().Add(-2 * time.Hour),
+		NotAfter:  time.Now().Add(-time.Hour),
+	})
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw}), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := newChainVerifier(caFile, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		chain          []*x509.Certificate
+		dstName        string
+		expectedValid  bool
+		expectedReason string
+	}{
+		{
+			name:           "valid",
+			chain:          []*x509.Certificate{leaf},
+			dstName:        "www.example.com",
+			expectedValid:  true,
+			expectedReason: reasonNone,
+		},
+		{
+			name:           "expired",
+			chain:          []*x509.Certificate{expired},
+			dstName:        "www.example.com",
+			expectedReason: reasonExpired,
+		},
+		{
+			name:           "hostname mismatch",
+			chain:          []*x509.Certificate{leaf},
+			dstName:        "mail.example.com",
+			expectedReason: reasonHostnameMismatch,
+		},
+		{
+			name:           "unknown authority",
+			chain:          []*x509.Certificate{selfSigned},
+			dstName:        "www.example.com",
+			expectedReason: reasonUnknownAuthority,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(te *testing.T) {
+			valid, reason := v.verify(test.chain, test.dstName)
+			assert.Equal(te, test.expectedValid, valid)
+			assert.Equal(te, test.expectedReason, reason)
+		})
+	}
+}
+
+func newTestCert(t *testing.T, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, template *x509.Certificate) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	if parent == nil {
+		parent = template
+		parentKey = key
+	}
+
+	b, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert, key
+}
@@ -15,6 +15,10 @@ var (
 	successDesc *prometheus.Desc
 	hopDesc     *prometheus.Desc
 	rttDesc     *prometheus.Desc
+	stateDesc   *prometheus.Desc
+
+	mplsHopsDesc       *prometheus.Desc
+	mplsLabelStackDesc *prometheus.Desc
 )
 
 func init() {
@@ -22,11 +26,16 @@ func init() {
 
 	successDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "success"), "Destination was reachable", labels, nil)
 	hopDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "hops"), "Number of hops", labels, nil)
-	rttDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "rtt"), "Round trip time in ms", labels, nil)
+	rttDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "rtt"), "Round trip time in ms (only if destination was reached)", labels, nil)
+	stateDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "state"), "Outcome of the traceroute (1 for the current state)", append(labels, "state"), nil)
+
+	mplsHopsDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "mpls_hops"), "Number of hops with MPLS label stack in ICMP extension (RFC 4950)", labels, nil)
+	mplsLabelStackDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "mpls_label_stack_info"), "MPLS label stack (top to bottom) reported by a hop", append(labels, "hop", "hop_addr", "label_stack"), nil)
 }
 
 type tracerouteExporter struct {
-	id string
+	id             string
+	icmpExtensions bool
 }
 
 // Export exports a prometheus metric
@@ -44,14 +53,31 @@ func (m *tracerouteExporter) Export(res *measurement.Result, probe *probe.Probe,
 		probe.Longitude(),
 	}
 
-	success, rtt := processLastHop(res)
+	state, rtt := pathState(res)
 	hops := float64(len(res.TracerouteResults()))
-	ch <- prometheus.MustNewConstMetric(successDesc, prometheus.GaugeValue, success, labelValues...)
+	ch <- prometheus.MustNewConstMetric(successDesc, prometheus.GaugeValue, boolToFloat(state == stateReached), labelValues...)
 	ch <- prometheus.MustNewConstMetric(hopDesc, prometheus.GaugeValue, hops, labelValues...)
 
+	for _, s := range states {
+		ch <- prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, boolToFloat(s == state), append(labelValues, s)...)
+	}
+
 	if rtt > 0 {
 		ch <- prometheus.MustNewConstMetric(rttDesc, prometheus.GaugeValue, rtt, labelValues...)
 	}
+
+	if m.icmpExtensions {
+		m.exportMPLS(res, labelValues, ch)
+	}
+}
+
+func (m *tracerouteExporter) exportMPLS(res *measurement.Result, labelValues []string, ch chan<- prometheus.Metric) {
+	hops := mplsHops(res.TracerouteResults())
+	ch <- prometheus.MustNewConstMetric(mplsHopsDesc, prometheus.GaugeValue, float64(len(hops)), labelValues...)
+
+	for _, h := range hops {
+		ch <- prometheus.MustNewConstMetric(mplsLabelStackDesc, prometheus.GaugeValue, 1, append(labelValues, strconv.Itoa(h.hop), h.from, h.labelStack())...)
+	}
 }
 
 // Describe exports metric descriptions for Prometheus
@@ -59,4 +85,18 @@ func (m *tracerouteExporter) Describe(ch chan<- *prometheus.Desc) {
 	ch <- successDesc
 	ch <- hopDesc
 	ch <- rttDesc
+	ch <- stateDesc
+
+	if m.icmpExtensions {
+		ch <- mplsHopsDesc
+		ch <- mplsLabelStackDesc
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
 }
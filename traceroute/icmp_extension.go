@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package traceroute
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/DNS-OARC/ripeatlas/measurement/traceroute"
+)
+
+const (
+	mplsClass = 1
+	mplsType  = 1
+)
+
+// mplsHop is a hop of a traceroute path with an MPLS label stack attached to the reply
+type mplsHop struct {
+	hop    int
+	from   string
+	labels []int
+}
+
+// labelStack returns the labels of the stack (top to bottom) as comma separated string
+func (h *mplsHop) labelStack() string {
+	l := make([]string, len(h.labels))
+	for i, label := range h.labels {
+		l[i] = strconv.Itoa(label)
+	}
+
+	return strings.Join(l, ",")
+}
+
+// mplsHops returns all hops of the path having at least one reply with an MPLS label stack (RFC 4950)
+func mplsHops(results []*traceroute.Result) []*mplsHop {
+	hops := make([]*mplsHop, 0)
+
+	for _, r := range results {
+		for _, rep := range r.Replies() {
+			labels := mplsLabels(rep.Icmpext())
+			if len(labels) == 0 {
+				continue
+			}
+
+			hops = append(hops, &mplsHop{
+				hop:    r.Hop(),
+				from:   rep.From(),
+				labels: labels,
+			})
+			break
+		}
+	}
+
+	return hops
+}
+
+func mplsLabels(ext *traceroute.Icmpext) []int {
+	if ext == nil {
+		return nil
+	}
+
+	labels := make([]int, 0)
+	for _, o := range ext.Objects() {
+		obj, ok := o.(map[string]interface{})
+		if !ok || intValue(obj["class"]) != mplsClass || intValue(obj["type"]) != mplsType {
+			continue
+		}
+
+		entries, ok := obj["mpls"].([]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, e := range entries {
+			entry, ok := e.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			labels = append(labels, intValue(entry["label"]))
+		}
+	}
+
+	return labels
+}
+
+func intValue(v interface{}) int {
+	f, ok := v.(float64)
+	if !ok {
+		return -1
+	}
+
+	return int(f)
+}
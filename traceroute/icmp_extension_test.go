@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package traceroute
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMPLSHops(t *testing.T) {
+	b := []byte(`{
+  "type": "traceroute",
+  "dst_addr": "192.0.2.1",
+  "result": [
+    {"hop": 1, "result": [{"from": "10.0.0.1", "rtt": 1.2}]},
+    {"hop": 2, "result": [
+      {"from": "10.0.1.1", "rtt": 5.1, "icmpext": {"version": 2, "rfc4884": 0, "obj": [
+        {"class": 1, "type": 1, "mpls": [
+          {"exp": 0, "label": 24005, "s": 0, "ttl": 1},
+          {"exp": 0, "label": 16001, "s": 1, "ttl": 1}
+        ]}
+      ]}},
+      {"from": "10.0.1.1", "rtt": 5.3, "icmpext": {"version": 2, "rfc4884": 0, "obj": [
+        {"class": 1, "type": 1, "mpls": [{"exp": 0, "label": 24005, "s": 1, "ttl": 1}]}
+      ]}}
+    ]},
+    {"hop": 3, "result": [
+      {"from": "10.0.2.1", "rtt": 7.9, "icmpext": {"version": 2, "rfc4884": 0, "obj": [{"class": 2, "type": 1}]}}
+    ]},
+    {"hop": 4, "result": [{"from": "192.0.2.1", "rtt": 9.5}]}
+  ]
+}`)
+
+	var r measurement.Result
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatal(err)
+	}
+
+	hops := mplsHops(r.TracerouteResults())
+	assert.Len(t, hops, 1)
+	assert.Equal(t, 2, hops[0].hop)
+	assert.Equal(t, "10.0.1.1", hops[0].from)
+	assert.Equal(t, "24005,16001", hops[0].labelStack())
+}
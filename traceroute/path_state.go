@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package traceroute
+
+import (
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/DNS-OARC/ripeatlas/measurement/traceroute"
+)
+
+const (
+	stateReached          = "reached"
+	stateTimeout          = "timeout"
+	stateUnreachableNet   = "unreachable_net"
+	stateUnreachableHost  = "unreachable_host"
+	stateUnreachableAdmin = "unreachable_admin"
+	stateUnreachable      = "unreachable"
+	stateLoop             = "loop"
+	stateIncomplete       = "incomplete"
+)
+
+var states = []string{
+	stateReached,
+	stateTimeout,
+	stateUnreachableNet,
+	stateUnreachableHost,
+	stateUnreachableAdmin,
+	stateUnreachable,
+	stateLoop,
+	stateIncomplete,
+}
+
+// pathState classifies the outcome of a traceroute. The RTT is only returned when the destination was reached
+// and is taken from the last reply sent by the destination.
+func pathState(r *measurement.Result) (state string, rtt float64) {
+	results := r.TracerouteResults()
+	if len(results) == 0 {
+		return stateIncomplete, 0
+	}
+
+	last := results[len(results)-1]
+	for _, rep := range last.Replies() {
+		if rep.From() == r.DstAddr() && len(rep.From()) > 0 {
+			state = stateReached
+
+			if rep.Rtt() > 0 {
+				rtt = rep.Rtt()
+			}
+		}
+	}
+
+	if state == stateReached {
+		return state, rtt
+	}
+
+	if s := unreachableState(last); len(s) > 0 {
+		return s, 0
+	}
+
+	if hasLoop(results, r.DstAddr()) {
+		return stateLoop, 0
+	}
+
+	if timedOut(last) {
+		return stateTimeout, 0
+	}
+
+	return stateIncomplete, 0
+}
+
+func unreachableState(hop *traceroute.Result) string {
+	for _, rep := range hop.Replies() {
+		switch rep.Err() {
+		case "":
+			continue
+		case "N":
+			return stateUnreachableNet
+		case "H":
+			return stateUnreachableHost
+		case "A":
+			return stateUnreachableAdmin
+		default:
+			return stateUnreachable
+		}
+	}
+
+	return ""
+}
+
+// hasLoop returns true if an address (other than the destination) responds on non consecutive hops
+func hasLoop(results []*traceroute.Result, dst string) bool {
+	seen := make(map[string]int)
+
+	for i, r := range results {
+		for _, rep := range r.Replies() {
+			from := rep.From()
+			if len(from) == 0 || from == dst {
+				continue
+			}
+
+			if j, found := seen[from]; found && i-j > 1 {
+				return true
+			}
+
+			seen[from] = i
+		}
+	}
+
+	return false
+}
+
+func timedOut(hop *traceroute.Result) bool {
+	for _, rep := range hop.Replies() {
+		if rep.X() != "*" {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package traceroute
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathState(t *testing.T) {
+	tests := []struct {
+		name          string
+		result        string
+		expectedState string
+		expectedRtt   float64
+	}{
+		{
+			name: "reached",
+			result: `[
+  {"hop": 1, "result": [{"from": "10.0.0.1", "rtt": 1.2}]},
+  {"hop": 2, "result": [{"from": "10.0.1.1", "rtt": 4.2}, {"from": "192.0.2.1", "rtt": 5.1}, {"x": "*"}]}
+]`,
+			expectedState: stateReached,
+			expectedRtt:   5.1,
+		},
+		{
+			name: "rtt of other address is ignored",
+			result: `[
+  {"hop": 1, "result": [{"from": "192.0.2.1", "rtt": 5.1}, {"from": "10.0.1.1", "rtt": 9.9}]}
+]`,
+			expectedState: stateReached,
+			expectedRtt:   5.1,
+		},
+		{
+			name: "timeout",
+			result: `[
+  {"hop": 1, "result": [{"from": "10.0.0.1", "rtt": 1.2}]},
+  {"hop": 255, "result": [{"x": "*"}, {"x": "*"}, {"x": "*"}]}
+]`,
+			expectedState: stateTimeout,
+		},
+		{
+			name: "network unreachable",
+			result: `[
+  {"hop": 1, "result": [{"from": "10.0.0.1", "rtt": 1.2}]},
+  {"hop": 2, "result": [{"from": "10.0.1.1", "rtt": 3.2, "err": "N"}]}
+]`,
+			expectedState: stateUnreachableNet,
+		},
+		{
+			name: "host unreachable",
+			result: `[
+  {"hop": 1, "result": [{"from": "10.0.0.1", "rtt": 1.2, "err": "H"}]}
+]`,
+			expectedState: stateUnreachableHost,
+		},
+		{
+			name: "administratively prohibited",
+			result: `[
+  {"hop": 1, "result": [{"x": "*"}, {"from": "10.0.0.1", "rtt": 1.2, "err": "A"}]}
+]`,
+			expectedState: stateUnreachableAdmin,
+		},
+		{
+			name: "loop",
+			result: `[
+  {"hop": 1, "result": [{"from": "10.0.0.1", "rtt": 1.2}]},
+  {"hop": 2, "result": [{"from": "10.0.1.1", "rtt": 2.2}]},
+  {"hop": 3, "result": [{"from": "10.0.0.1", "rtt": 3.2}]},
+  {"hop": 4, "result": [{"x": "*"}]}
+]`,
+			expectedState: stateLoop,
+		},
+		{
+			name: "incomplete",
+			result: `[
+  {"hop": 1, "result": [{"from": "10.0.0.1", "rtt": 1.2}]},
+  {"hop": 2, "result": [{"from": "10.0.1.1", "rtt": 2.2}]}
+]`,
+			expectedState: stateIncomplete,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(te *testing.T) {
+			var r measurement.Result
+			err := json.Unmarshal([]byte(`{"type": "traceroute", "dst_addr": "192.0.2.1", "result": `+test.result+`}`), &r)
+			if err != nil {
+				te.Fatal(err)
+			}
+
+			state, rtt := pathState(&r)
+			assert.Equal(te, test.expectedState, state)
+			assert.Equal(te, test.expectedRtt, rtt)
+		})
+	}
+}
@@ -4,21 +4,25 @@ package traceroute
 
 import (
 	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/czerwonk/atlas_exporter/config"
 	"github.com/czerwonk/atlas_exporter/exporter"
+	"github.com/czerwonk/atlas_exporter/probe"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 type rttHistogram struct {
-	rtt prometheus.Histogram
+	rtt       *prometheus.HistogramVec
+	partition *exporter.HistogramPartition
 }
 
-func newRttHistogram(id, ipVersion string, buckets []float64) exporter.Histogram {
+func newRttHistogram(id, ipVersion string, buckets []float64, native config.NativeHistogram, partition *exporter.HistogramPartition) exporter.Histogram {
 	if buckets == nil {
 		buckets = []float64{10, 20, 50, 100}
 	}
 
 	return &rttHistogram{
-		rtt: prometheus.NewHistogram(prometheus.HistogramOpts{
+		partition: partition,
+		rtt: partition.NewHistogramVec(exporter.WithNativeHistogram(prometheus.HistogramOpts{
 			Namespace: ns,
 			Subsystem: sub,
 			Name:      "rtt_hist",
@@ -28,17 +32,26 @@ func newRttHistogram(id, ipVersion string, buckets []float64) exporter.Histogram
 				"measurement": id,
 				"ip_version":  ipVersion,
 			},
-		}),
+		}, native)),
 	}
 }
 
-func (h *rttHistogram) ProcessResult(r *measurement.Result) {
-	success, rtt := processLastHop(r)
-	if success == 1 && rtt > 0 {
-		h.rtt.Observe(rtt)
+func (h *rttHistogram) ProcessResult(r *measurement.Result, probe *probe.Probe) {
+	for _, rtt := range rtts(r) {
+		h.rtt.WithLabelValues(h.partition.LabelValues(probe, r.Af())...).Observe(rtt)
 	}
 }
 
-func (h *rttHistogram) Hist() prometheus.Histogram {
+func (h *rttHistogram) Hist() prometheus.Collector {
 	return h.rtt
 }
+
+// rtts returns the round trip time to the destination (empty if not reached)
+func rtts(r *measurement.Result) []float64 {
+	state, rtt := pathState(r)
+	if state == stateReached && rtt > 0 {
+		return []float64{rtt}
+	}
+
+	return nil
+}
@@ -3,9 +3,9 @@
 package traceroute
 
 import (
-	"github.com/DNS-OARC/ripeatlas/measurement"
 	"github.com/czerwonk/atlas_exporter/config"
 	"github.com/czerwonk/atlas_exporter/exporter"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 const (
@@ -13,35 +13,26 @@ const (
 	sub = "traceroute"
 )
 
+func init() {
+	exporter.Register("traceroute", NewMeasurement)
+}
+
 // NewMeasurement returns a new instance of `exorter.Measurement` for a traceroute measurement
 func NewMeasurement(id, ipVersion string, cfg *config.Config) *exporter.Measurement {
+	buckets := cfg.HistogramBucketsForMeasurement(id)
+	partition := exporter.NewHistogramPartition(cfg.HistogramPartition)
 	opts := []exporter.MeasurementOpt{
-		exporter.WithHistograms(newRttHistogram(id, ipVersion, cfg.HistogramBuckets.Traceroute.Rtt)),
+		exporter.WithHistograms(newRttHistogram(id, ipVersion, buckets.Traceroute.Rtt, buckets.Traceroute.Native, partition)),
 	}
 
-	if cfg.FilterInvalidResults {
-		opts = append(opts, exporter.WithValidator(&tracerouteResultValidator{}))
+	if m, _ := cfg.MeasurementByID(id); m.Summary.Enabled() {
+		opts = append(opts, exporter.WithHistograms(exporter.NewRttSummary(prometheus.BuildFQName(ns, sub, "rtt_summary"),
+			"Summary of round trip times over all traceroute requests within a sliding window", id, ipVersion, m.Summary, partition, exporter.ResultValues(rtts))))
 	}
 
-	return exporter.NewMeasurement(&tracerouteExporter{id}, opts...)
-}
-
-func processLastHop(r *measurement.Result) (success float64, rtt float64) {
-	if len(r.TracerouteResults()) == 0 {
-		return success, rtt
-	}
-
-	last := r.TracerouteResults()[len(r.TracerouteResults())-1]
-	for _, rep := range last.Replies() {
-		if rep.From() == r.DstAddr() {
-			success = 1
-		}
-
-		repl := last.Replies()
-		if len(repl) > 0 {
-			rtt = repl[len(repl)-1].Rtt()
-		}
+	if cfg.FilterInvalidResults {
+		opts = append(opts, exporter.WithValidator(&tracerouteResultValidator{}))
 	}
 
-	return success, rtt
+	return exporter.NewMeasurement(&tracerouteExporter{id: id, icmpExtensions: cfg.Traceroute.ICMPExtensions}, opts...)
 }
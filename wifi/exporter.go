@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package wifi
+
+import (
+	"strconv"
+
+	"github.com/DNS-OARC/ripeatlas/measurement"
+	"github.com/czerwonk/atlas_exporter/probe"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	labels          []string
+	successDesc     *prometheus.Desc
+	associatedDesc  *prometheus.Desc
+	authorizedDesc  *prometheus.Desc
+	connectTimeDesc *prometheus.Desc
+)
+
+func init() {
+	labels = []string{"measurement", "probe", "ssid", "bssid", "asn", "ip_version", "country_code", "lat", "long"}
+
+	successDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "success"), "Probe was associated with the network and authorized", labels, nil)
+	associatedDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "associated"), "Probe was associated with the access point", labels, nil)
+	authorizedDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "authorized"), "802.1X authentication succeeded", labels, nil)
+	connectTimeDesc = prometheus.NewDesc(prometheus.BuildFQName(ns, sub, "connect_time_seconds"), "Time to connect to the network", labels, nil)
+}
+
+type wifiExporter struct {
+	id string
+}
+
+// Export exports a prometheus metric
+func (m *wifiExporter) Export(res *measurement.Result, probe *probe.Probe, ch chan<- prometheus.Metric) {
+	status := supplicantStatus(res.WpaSupplicant())
+
+	labelValues := []string{
+		m.id,
+		strconv.Itoa(probe.ID),
+		status.ssid(),
+		status.bssid(),
+		strconv.Itoa(probe.ASNForIPVersion(res.Af())),
+		strconv.Itoa(res.Af()),
+		probe.CountryCode,
+		probe.Latitude(),
+		probe.Longitude(),
+	}
+
+	associated := status.associated()
+	authorized := associated && status.authorized()
+	ch <- prometheus.MustNewConstMetric(successDesc, prometheus.GaugeValue, boolToFloat(authorized), labelValues...)
+	ch <- prometheus.MustNewConstMetric(associatedDesc, prometheus.GaugeValue, boolToFloat(associated), labelValues...)
+	ch <- prometheus.MustNewConstMetric(authorizedDesc, prometheus.GaugeValue, boolToFloat(authorized), labelValues...)
+
+	if d, found := status.connectTime(); found {
+		ch <- prometheus.MustNewConstMetric(connectTimeDesc, prometheus.GaugeValue, d, labelValues...)
+	}
+}
+
+// Describe exports metric descriptions for Prometheus
+func (m *wifiExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- successDesc
+	ch <- associatedDesc
+	ch <- authorizedDesc
+	ch <- connectTimeDesc
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package wifi
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Keys of the wpa_supplicant status reported by the probe. The format of WiFi results is not documented by RIPE,
+// so the keys are taken from the status output of wpa_supplicant and results seen in the wild.
+const (
+	keySSID       = "ssid"
+	keyBSSID      = "bssid"
+	keyWpaState   = "wpa_state"
+	keyPortStatus = "suppPortStatus"
+	// keyConnectTime is added to the wpa_supplicant status by the probe firmware (time to connect in seconds)
+	keyConnectTime = "connect-time"
+
+	wpaStateCompleted = "COMPLETED"
+	portAuthorized    = "Authorized"
+)
+
+// supplicantStatus is the wpa_supplicant status of a WiFi measurement result
+type supplicantStatus map[string]string
+
+func (s supplicantStatus) ssid() string {
+	return s[keySSID]
+}
+
+func (s supplicantStatus) bssid() string {
+	return strings.ToLower(s[keyBSSID])
+}
+
+// associated returns true if the probe was associated with the access point
+func (s supplicantStatus) associated() bool {
+	return s[keyWpaState] == wpaStateCompleted
+}
+
+// authorized returns true if the 802.1X authentication succeeded (true if no 802.1X authentication is used)
+func (s supplicantStatus) authorized() bool {
+	v, found := s[keyPortStatus]
+	return !found || v == portAuthorized
+}
+
+// connectTime returns the time the probe needed to connect to the network in seconds
+func (s supplicantStatus) connectTime() (float64, bool) {
+	v, found := s[keyConnectTime]
+	if !found {
+		return 0, false
+	}
+
+	d, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil || d < 0 {
+		return 0, false
+	}
+
+	return d, true
+}
@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package wifi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSupplicantStatus(t *testing.T) {
+	s := supplicantStatus{
+		"ssid":           "eduroam",
+		"bssid":          "AA:BB:CC:DD:EE:FF",
+		"wpa_state":      "COMPLETED",
+		"suppPortStatus": "Unauthorized",
+		"connect-time":   " 1.25",
+	}
+
+	assert.Equal(t, "aa:bb:cc:dd:ee:ff", s.bssid())
+	assert.True(t, s.associated())
+	assert.False(t, s.authorized())
+
+	d, found := s.connectTime()
+	assert.True(t, found)
+	assert.Equal(t, 1.25, d)
+
+	_, found = supplicantStatus{"connect-time": "n/a"}.connectTime()
+	assert.False(t, found)
+
+	assert.True(t, supplicantStatus{"wpa_state": "COMPLETED"}.authorized())
+}
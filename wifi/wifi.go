@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+
+package wifi
+
+import (
+	"github.com/czerwonk/atlas_exporter/config"
+	"github.com/czerwonk/atlas_exporter/exporter"
+)
+
+const (
+	ns  = "atlas"
+	sub = "wifi"
+)
+
+func init() {
+	exporter.Register("wifi", NewMeasurement)
+}
+
+// NewMeasurement returns a new instance of `exorter.Measurement` for a WiFi measurement
+func NewMeasurement(id, ipVersion string, cfg *config.Config) *exporter.Measurement {
+	opts := []exporter.MeasurementOpt{}
+
+	if cfg.FilterInvalidResults {
+		opts = append(opts, exporter.WithValidator(&exporter.DefaultResultValidator{}))
+	}
+
+	return exporter.NewMeasurement(&wifiExporter{id}, opts...)
+}